@@ -2,7 +2,8 @@ package account
 
 import (
 	"fmt"
-	"strconv"
+	"sync"
+	"time"
 
 	icheck "github.com/icheckteam/icheck-go"
 )
@@ -10,88 +11,94 @@ import (
 // Client is used to invoke /account APIs.
 type Client struct {
 	B icheck.Backend
+
+	mu       sync.Mutex
+	resendAt map[string]time.Time
 }
 
 // Me get current user
 func (c *Client) Me(params *icheck.Params) (*icheck.User, error) {
-	res := &icheck.UserResponse{}
-	err := c.B.Call("GET", "/account", nil, params, res)
-
+	env, err := c.MeEnvelope(params)
 	if err != nil {
 		return nil, err
 	}
-	return res.User, nil
+	return env.Data, nil
+}
+
+// MeEnvelope is Me returning the whole response envelope.
+func (c *Client) MeEnvelope(params *icheck.Params) (*icheck.Envelope[*icheck.User], error) {
+	return icheck.CallEnvelope[*icheck.User](c.B, "GET", "/account", nil, params)
 }
 
-// Login login user
+// Login login user. It returns *icheck.ErrMFARequired when the user must
+// also enter a two-factor code, to be sent with CompleteMFA.
 func (c *Client) Login(params *icheck.LoginParams) (*icheck.AccessToken, error) {
-	body := &icheck.RequestValues{}
-	if params.Username != "" {
-		body.Add("username", params.Username)
-	}
-	if params.Password != "" {
-		body.Add("password", params.Password)
-	}
-	if params.TTL > 0 {
-		body.Add("ttl", strconv.FormatInt(params.TTL, 10))
+	data := *params
+	data.Username = normalizeUsername(data.Username)
+	body, err := icheck.EncodeForm(&data)
+	if err != nil {
+		return nil, err
 	}
 	resp := &icheck.LoginResponse{}
-	err := c.B.Call("POST", "/login", body, nil, resp)
+	err = c.B.Call("POST", "/login", body, nil, resp)
 	if err != nil {
 		return nil, err
 	}
-	return resp.Data, nil
+	return resp.Token()
 }
 
-// Login login user
-func (c *Client) Logout(params *icheck.Params) (interface{}, error) {
-	resp := make(map[string]interface{})
+// Logout revokes the access token of params
+func (c *Client) Logout(params *icheck.Params) (*icheck.RevokedSession, error) {
+	resp := &icheck.LogoutResponse{}
 	err := c.B.Call("POST", "/logout", nil, params, resp)
 	if err != nil {
 		return nil, err
 	}
-	return resp["data"], nil
+	return resp.Data, nil
 }
 
-// LoginWithSocial ....
+// LoginWithSocial exchanges an authorization code issued by params.Provider
+// for an access token.
 func (c *Client) LoginWithSocial(params *icheck.LoginSocialParams) (*icheck.AccessToken, error) {
-	body := &icheck.RequestValues{}
-	if params.Code != "" {
-		body.Add("code", params.Code)
-	}
-	if params.TTL > 0 {
-		body.Add("ttl", strconv.FormatInt(params.TTL, 10))
+	if !params.Provider.Valid() {
+		return nil, &icheck.ErrUnknownProvider{Provider: params.Provider}
 	}
-
-	if params.Provider == "" {
-		params.Provider = "facebook"
+	body, err := icheck.EncodeForm(params)
+	if err != nil {
+		return nil, err
 	}
 
 	resp := &icheck.LoginResponse{}
 
-	err := c.B.Call("GET", fmt.Sprintf("/auth/%s", params.Provider), body, nil, resp)
+	err = c.B.Call("POST", fmt.Sprintf("/auth/%s", params.Provider), body, nil, resp)
 	if err != nil {
 		return nil, err
 	}
-	return resp.Data, nil
+	return resp.Token()
 }
 
 // Register register an user
 func (c *Client) Register(params *icheck.RegisterParams) (*icheck.UserResponse, error) {
-	body := &icheck.RequestValues{}
-	if params.Username != "" {
-		body.Add("username", params.Username)
-	}
-	if params.Password != "" {
-		body.Add("password", params.Password)
-	}
-	if params.Name != "" {
-		body.Add("name", params.Name)
+	data := *params
+	data.Username = normalizeUsername(data.Username)
+	body, err := icheck.EncodeForm(&data)
+	if err != nil {
+		return nil, err
 	}
 	resp := &icheck.UserResponse{}
-	err := c.B.Call("POST", "/register", body, nil, resp)
+	err = c.B.Call("POST", "/register", body, nil, resp)
 	if err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
+
+// normalizeUsername turns phone numbers into the national format the API
+// expects, so "+84 977 465 849" and "0977465849" name the same user. Other
+// usernames, such as emails, are left as is.
+func normalizeUsername(username string) string {
+	if phone, err := icheck.LocalPhone(username); err == nil {
+		return phone
+	}
+	return username
+}
@@ -2,6 +2,8 @@ package account
 
 import (
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/Sirupsen/logrus"
@@ -31,3 +33,27 @@ func TestLogin(t *testing.T) {
 
 	log.Print(user)
 }
+
+// newTestClient returns a Client calling a test server that serves handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &Client{B: &icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}}
+}
+
+func TestLogout(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/logout" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"status":200,"data":{"id":"s1","user_id":7,"revoked_at":"2017-03-01T10:00:00Z"}}`))
+	})
+
+	revoked, err := c.Logout(&icheck.Params{AccessToken: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revoked.ID != "s1" || revoked.UserID != 7 || revoked.RevokedAt.IsZero() {
+		t.Errorf("revoked = %+v", revoked)
+	}
+}
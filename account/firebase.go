@@ -0,0 +1,16 @@
+package account
+
+import (
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// FirebaseToken requests a new Firebase custom token for the user of params.
+// The token is in the FirebaseToken field of the result.
+func (c *Client) FirebaseToken(params *icheck.Params) (*icheck.AccessToken, error) {
+	resp := &icheck.LoginResponse{}
+	err := c.B.Call("POST", "/account/firebase-token", nil, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Token()
+}
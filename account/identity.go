@@ -0,0 +1,50 @@
+package account
+
+import (
+	"fmt"
+	"net/http"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// LinkSocial links the account of data.Provider authorizing data.Code to
+// the current user. CodeVerifier and RedirectURI must be set for codes
+// obtained with PKCE, e.g. through oauth.Flow. It returns
+// *icheck.ErrIdentityConflict if that account belongs to another user.
+func (c *Client) LinkSocial(data *icheck.LinkSocialParams, params *icheck.Params) (*icheck.Identity, error) {
+	provider := data.Provider
+	if !provider.Valid() {
+		return nil, &icheck.ErrUnknownProvider{Provider: provider}
+	}
+	body, err := icheck.EncodeForm(data)
+	if err != nil {
+		return nil, err
+	}
+	resp := &icheck.IdentityResponse{}
+	err = c.B.Call("POST", fmt.Sprintf("/account/identities/%s", provider), body, params, resp)
+	if apiErr, ok := err.(*icheck.Error); ok && apiErr.Status == http.StatusConflict {
+		return nil, &icheck.ErrIdentityConflict{Provider: provider, Message: apiErr.Message}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// UnlinkSocial unlinks the account of provider from the current user.
+func (c *Client) UnlinkSocial(provider icheck.SocialProvider, params *icheck.Params) error {
+	if !provider.Valid() {
+		return &icheck.ErrUnknownProvider{Provider: provider}
+	}
+	return c.B.Call("DELETE", fmt.Sprintf("/account/identities/%s", provider), nil, params, &icheck.IdentityResponse{})
+}
+
+// ListIdentities returns the social accounts linked to the current user.
+func (c *Client) ListIdentities(params *icheck.Params) ([]*icheck.Identity, error) {
+	resp := &icheck.IdentityListResponse{}
+	err := c.B.Call("GET", "/account/identities", nil, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
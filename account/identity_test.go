@@ -0,0 +1,45 @@
+package account
+
+import (
+	"net/http"
+	"testing"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestLinkSocialConflict(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/account/identities/google" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"status":409,"message":"already linked"}`))
+	})
+
+	_, err := c.LinkSocial(&icheck.LinkSocialParams{Provider: icheck.ProviderGoogle, Code: "code"}, nil)
+	conflict, ok := err.(*icheck.ErrIdentityConflict)
+	if !ok {
+		t.Fatalf("err = %#v, want *icheck.ErrIdentityConflict", err)
+	}
+	if conflict.Provider != icheck.ProviderGoogle || conflict.Message != "already linked" {
+		t.Errorf("conflict = %+v", conflict)
+	}
+
+	if _, err := c.LinkSocial(&icheck.LinkSocialParams{Provider: "myspace", Code: "code"}, nil); err == nil {
+		t.Error("unknown provider accepted")
+	}
+}
+
+func TestListIdentities(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":200,"data":[{"provider":"facebook","provider_user_id":"42"}]}`))
+	})
+
+	ids, err := c.ListIdentities(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0].Provider != icheck.ProviderFacebook || ids[0].ProviderUserID != "42" {
+		t.Errorf("identities = %+v", ids)
+	}
+}
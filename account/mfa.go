@@ -0,0 +1,66 @@
+package account
+
+import (
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// EnrollMFA creates a TOTP secret for the current user. Two-factor
+// authentication is enabled once VerifyMFA confirms a code generated from
+// it.
+func (c *Client) EnrollMFA(params *icheck.Params) (*icheck.MFAEnrollment, error) {
+	resp := &icheck.MFAEnrollmentResponse{}
+	err := c.B.Call("POST", "/account/mfa", nil, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// VerifyMFA enables two-factor authentication with a code from the enrolled
+// secret, and returns the recovery codes of the user.
+func (c *Client) VerifyMFA(code string, params *icheck.Params) (*icheck.RecoveryCodes, error) {
+	return c.mfaCodes("/account/mfa/verify", code, params)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the current user.
+func (c *Client) RegenerateRecoveryCodes(code string, params *icheck.Params) (*icheck.RecoveryCodes, error) {
+	return c.mfaCodes("/account/mfa/recovery-codes", code, params)
+}
+
+// DisableMFA disables two-factor authentication. code is a TOTP code or a
+// recovery code.
+func (c *Client) DisableMFA(code string, params *icheck.Params) error {
+	body, err := icheck.EncodeForm(&icheck.MFACodeParams{Code: code})
+	if err != nil {
+		return err
+	}
+	return c.B.Call("DELETE", "/account/mfa", body, params, &icheck.UserResponse{})
+}
+
+// CompleteMFA finishes a login that returned *icheck.ErrMFARequired. code
+// is a TOTP code or a recovery code.
+func (c *Client) CompleteMFA(challengeID, code string) (*icheck.AccessToken, error) {
+	body, err := icheck.EncodeForm(&icheck.CompleteMFAParams{ChallengeID: challengeID, Code: code})
+	if err != nil {
+		return nil, err
+	}
+	resp := &icheck.LoginResponse{}
+	err = c.B.Call("POST", "/login/mfa", body, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Token()
+}
+
+func (c *Client) mfaCodes(path, code string, params *icheck.Params) (*icheck.RecoveryCodes, error) {
+	body, err := icheck.EncodeForm(&icheck.MFACodeParams{Code: code})
+	if err != nil {
+		return nil, err
+	}
+	resp := &icheck.RecoveryCodesResponse{}
+	err = c.B.Call("POST", path, body, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
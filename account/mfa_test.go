@@ -0,0 +1,49 @@
+package account
+
+import (
+	"net/http"
+	"testing"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestLoginMFA(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/login":
+			w.Write([]byte(`{"status":200,"mfa":{"id":"ch1"}}`))
+		case "/login/mfa":
+			if r.PostForm.Get("challenge_id") != "ch1" || r.PostForm.Get("code") != "123456" {
+				t.Errorf("form = %v", r.PostForm)
+			}
+			w.Write([]byte(`{"status":200,"data":{"id":"tok"}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	})
+
+	_, err := c.Login(&icheck.LoginParams{Username: "an@example.com", Password: "secret123"})
+	mfa, ok := err.(*icheck.ErrMFARequired)
+	if !ok {
+		t.Fatalf("err = %#v, want *icheck.ErrMFARequired", err)
+	}
+
+	token, err := c.CompleteMFA(mfa.Challenge.ID, "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.ID != "tok" {
+		t.Errorf("token ID = %q", token.ID)
+	}
+}
+
+func TestCompleteMFAWithoutToken(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":200}`))
+	})
+
+	if _, err := c.CompleteMFA("ch1", "123456"); err != icheck.ErrNoAccessToken {
+		t.Errorf("err = %v, want icheck.ErrNoAccessToken", err)
+	}
+}
@@ -0,0 +1,51 @@
+package account
+
+import (
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// ChangePassword changes the password of the current user. The new password
+// is checked with icheck.ValidatePassword before calling the API.
+func (c *Client) ChangePassword(oldPassword, newPassword string, params *icheck.Params) error {
+	if err := icheck.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+	body, err := icheck.EncodeForm(&icheck.ChangePasswordParams{
+		OldPassword: oldPassword,
+		NewPassword: newPassword,
+	})
+	if err != nil {
+		return err
+	}
+	return c.B.Call("POST", "/account/password", body, params, &icheck.UserResponse{})
+}
+
+// ForgotPassword sends a password reset code by SMS or email.
+func (c *Client) ForgotPassword(params *icheck.ForgotPasswordParams) (*icheck.Verification, error) {
+	data := *params
+	data.Username = normalizeUsername(data.Username)
+	body, err := icheck.EncodeForm(&data)
+	if err != nil {
+		return nil, err
+	}
+	resp := &icheck.VerificationResponse{}
+	err = c.B.Call("POST", "/password/forgot", body, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// ResetPassword sets a new password using the code sent by ForgotPassword.
+func (c *Client) ResetPassword(params *icheck.ResetPasswordParams) error {
+	if err := icheck.ValidatePassword(params.Password); err != nil {
+		return err
+	}
+	data := *params
+	data.Username = normalizeUsername(data.Username)
+	body, err := icheck.EncodeForm(&data)
+	if err != nil {
+		return err
+	}
+	return c.B.Call("POST", "/password/reset", body, nil, &icheck.UserResponse{})
+}
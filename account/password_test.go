@@ -0,0 +1,73 @@
+package account
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestPassword(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form := r.PostForm
+		switch r.Method + " " + r.URL.Path {
+		case "POST /account/password":
+			if form.Get("old_password") != "0ld-Secret" || form.Get("new_password") != "n3w-Secret" {
+				t.Errorf("form = %v", form)
+			}
+			w.Write([]byte(`{"status":200,"data":{"id":7}}`))
+		case "POST /password/forgot":
+			if form.Get("username") != "0977465849" || form.Get("channel") != "phone" {
+				t.Errorf("form = %v", form)
+			}
+			w.Write([]byte(`{"status":200,"data":{"channel":"phone","destination":"*******849"}}`))
+		case "POST /password/reset":
+			if form.Get("username") != "0977465849" || form.Get("code") != "123456" || form.Get("password") != "n3w-Secret" {
+				t.Errorf("form = %v", form)
+			}
+			w.Write([]byte(`{"status":200,"data":{"id":7}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	if err := c.ChangePassword("0ld-Secret", "n3w-Secret", &icheck.Params{AccessToken: "t"}); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := c.ForgotPassword(&icheck.ForgotPasswordParams{Username: "+84 977465849", Channel: icheck.VerificationPhone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Destination != "*******849" {
+		t.Errorf("verification = %+v", v)
+	}
+
+	err = c.ResetPassword(&icheck.ResetPasswordParams{Username: "+84977465849", Code: "123456", Password: "n3w-Secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWeakPasswordIsNotSent(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	err := c.ChangePassword("0ld-Secret", "short", nil)
+	if _, ok := err.(*icheck.ErrBadRequest); !ok {
+		t.Errorf("ChangePassword: got %v, want *icheck.ErrBadRequest", err)
+	}
+
+	err = c.ResetPassword(&icheck.ResetPasswordParams{Username: "0977465849", Code: "123456", Password: "lettersonly"})
+	if _, ok := err.(*icheck.ErrBadRequest); !ok {
+		t.Errorf("ResetPassword: got %v, want *icheck.ErrBadRequest", err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Errorf("server got %d requests, want 0", n)
+	}
+}
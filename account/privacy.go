@@ -0,0 +1,56 @@
+package account
+
+import (
+	"fmt"
+	"net/url"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// RequestDataExport starts building an archive of the personal data of the
+// current user. Poll DataExport until the archive is ready to download.
+func (c *Client) RequestDataExport(params *icheck.Params) (*icheck.DataExport, error) {
+	resp := &icheck.DataExportResponse{}
+	err := c.B.Call("POST", "/account/exports", nil, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// DataExport returns the export id of the current user.
+func (c *Client) DataExport(id string, params *icheck.Params) (*icheck.DataExport, error) {
+	resp := &icheck.DataExportResponse{}
+	err := c.B.Call("GET", fmt.Sprintf("/account/exports/%s", url.PathEscape(id)), nil, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// DeleteAccount schedules the deletion of the current user at the end of
+// the grace period. The account.deleted webhook event is sent once it is
+// done.
+func (c *Client) DeleteAccount(confirmation string, params *icheck.Params) (*icheck.AccountDeletion, error) {
+	body, err := icheck.EncodeForm(&icheck.DeleteAccountParams{Confirmation: confirmation})
+	if err != nil {
+		return nil, err
+	}
+	resp := &icheck.AccountDeletionResponse{}
+	err = c.B.Call("POST", "/account/deletion", body, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// CancelAccountDeletion cancels a deletion scheduled by DeleteAccount during
+// its grace period.
+func (c *Client) CancelAccountDeletion(params *icheck.Params) (*icheck.AccountDeletion, error) {
+	resp := &icheck.AccountDeletionResponse{}
+	err := c.B.Call("DELETE", "/account/deletion", nil, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,69 @@
+package account
+
+import (
+	"net/http"
+	"testing"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestDataExport(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /account/exports":
+			w.Write([]byte(`{"status":200,"data":{"id":"e1","status":"pending"}}`))
+		case "GET /account/exports/e1":
+			w.Write([]byte(`{"status":200,"data":{"id":"e1","status":"ready","url":"https://cdn.icheck.vn/e1.zip"}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	export, err := c.RequestDataExport(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export.ID != "e1" || export.Status != icheck.DataExportPending {
+		t.Errorf("export = %+v", export)
+	}
+
+	export, err = c.DataExport("e1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export.Status != icheck.DataExportReady || export.URL != "https://cdn.icheck.vn/e1.zip" {
+		t.Errorf("export = %+v", export)
+	}
+}
+
+func TestAccountDeletion(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /account/deletion":
+			if confirmation := r.PostFormValue("confirmation"); confirmation != "secret" {
+				t.Errorf("confirmation = %q", confirmation)
+			}
+			w.Write([]byte(`{"status":200,"data":{"user_id":7,"status":"scheduled","delete_at":"2017-04-01T00:00:00Z"}}`))
+		case "DELETE /account/deletion":
+			w.Write([]byte(`{"status":200,"data":{"user_id":7,"status":"cancelled"}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	deletion, err := c.DeleteAccount("secret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deletion.UserID != 7 || deletion.Status != icheck.AccountDeletionScheduled || deletion.DeleteAt.IsZero() {
+		t.Errorf("deletion = %+v", deletion)
+	}
+
+	deletion, err = c.CancelAccountDeletion(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deletion.Status != icheck.AccountDeletionCancelled {
+		t.Errorf("deletion = %+v", deletion)
+	}
+}
@@ -0,0 +1,39 @@
+package account
+
+import (
+	"fmt"
+	"net/url"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// Sessions lists the active access tokens of the current user.
+func (c *Client) Sessions(params *icheck.Params) ([]*icheck.Session, error) {
+	resp := &icheck.SessionListResponse{}
+	err := c.B.Call("GET", "/account/sessions", nil, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// RevokeSession revokes the access token of the session id.
+func (c *Client) RevokeSession(id string, params *icheck.Params) (*icheck.RevokedSession, error) {
+	resp := &icheck.LogoutResponse{}
+	err := c.B.Call("DELETE", fmt.Sprintf("/account/sessions/%s", url.PathEscape(id)), nil, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// RevokeAllOtherSessions revokes every access token of the current user but
+// the one of params.
+func (c *Client) RevokeAllOtherSessions(params *icheck.Params) ([]*icheck.RevokedSession, error) {
+	resp := &icheck.RevokedSessionListResponse{}
+	err := c.B.Call("DELETE", "/account/sessions", nil, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,45 @@
+package account
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSessions(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /account/sessions":
+			w.Write([]byte(`{"status":200,"data":[{"id":"s1","device":"iPhone","ttl":3600,"current":true},{"id":"s2"}]}`))
+		case "DELETE /account/sessions/s2":
+			w.Write([]byte(`{"status":200,"data":{"id":"s2"}}`))
+		case "DELETE /account/sessions":
+			w.Write([]byte(`{"status":200,"data":[{"id":"s2"},{"id":"s3"}]}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	sessions, err := c.Sessions(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || !sessions[0].Current || sessions[0].Device != "iPhone" || sessions[0].TTL != 3600 {
+		t.Errorf("sessions = %+v", sessions)
+	}
+
+	revoked, err := c.RevokeSession("s2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revoked.ID != "s2" {
+		t.Errorf("revoked = %+v", revoked)
+	}
+
+	all, err := c.RevokeAllOtherSessions(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("revoked %d sessions, want 2", len(all))
+	}
+}
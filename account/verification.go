@@ -0,0 +1,90 @@
+package account
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// SendVerification sends a verification code to the phone number or email
+// address of the current user.
+func (c *Client) SendVerification(channel icheck.VerificationChannel, params *icheck.Params) (*icheck.Verification, error) {
+	return c.sendVerification(&icheck.VerificationParams{Channel: channel}, params)
+}
+
+// ResendVerification sends a new verification code. It returns
+// *icheck.ErrResendCooldown without calling the API if the previous code was
+// sent too recently.
+func (c *Client) ResendVerification(channel icheck.VerificationChannel, params *icheck.Params) (*icheck.Verification, error) {
+	key := cooldownKey(channel, params)
+
+	c.mu.Lock()
+	retryAt := c.resendAt[key]
+	if !time.Now().Before(retryAt) {
+		delete(c.resendAt, key)
+	}
+	c.mu.Unlock()
+	if time.Now().Before(retryAt) {
+		return nil, &icheck.ErrResendCooldown{RetryAt: retryAt}
+	}
+
+	return c.sendVerification(&icheck.VerificationParams{Channel: channel, Resend: true}, params)
+}
+
+// ConfirmVerification confirms the code sent by SendVerification and returns
+// the user with its phone or email marked as verified.
+func (c *Client) ConfirmVerification(code string, params *icheck.Params) (*icheck.User, error) {
+	body, err := icheck.EncodeForm(&icheck.ConfirmVerificationParams{Code: code})
+	if err != nil {
+		return nil, err
+	}
+	resp := &icheck.UserResponse{}
+	err = c.B.Call("POST", "/account/verification/confirm", body, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+func (c *Client) sendVerification(data *icheck.VerificationParams, params *icheck.Params) (*icheck.Verification, error) {
+	body, err := icheck.EncodeForm(data)
+	if err != nil {
+		return nil, err
+	}
+	resp := &icheck.VerificationResponse{}
+	err = c.B.Call("POST", "/account/verification", body, params, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Data != nil && !resp.Data.ResendAt.IsZero() {
+		c.mu.Lock()
+		if c.resendAt == nil {
+			c.resendAt = make(map[string]time.Time)
+		}
+		// Drop the cooldowns that have passed, e.g. of tokens that were
+		// never used again.
+		now := time.Now()
+		for key, retryAt := range c.resendAt {
+			if !now.Before(retryAt) {
+				delete(c.resendAt, key)
+			}
+		}
+		c.resendAt[cooldownKey(data.Channel, params)] = resp.Data.ResendAt
+		c.mu.Unlock()
+	}
+	return resp.Data, nil
+}
+
+// cooldownKey identifies the cooldown of channel for the user of params by
+// a hash of the access token, so that tokens are not kept in memory.
+func cooldownKey(channel icheck.VerificationChannel, params *icheck.Params) string {
+	scope := ""
+	if params != nil && params.AccessToken != "" {
+		sum := sha256.Sum256([]byte(params.AccessToken))
+		scope = hex.EncodeToString(sum[:16])
+	}
+	return string(channel) + " " + scope
+}
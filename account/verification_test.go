@@ -0,0 +1,111 @@
+package account
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestVerification(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /account/verification":
+			if channel := r.PostFormValue("channel"); channel != "email" {
+				t.Errorf("channel = %q", channel)
+			}
+			w.Write([]byte(`{"status":200,"data":{"channel":"email","destination":"n***@icheck.vn"}}`))
+		case "POST /account/verification/confirm":
+			if code := r.PostFormValue("code"); code != "123456" {
+				t.Errorf("code = %q", code)
+			}
+			w.Write([]byte(`{"status":200,"data":{"id":7,"email_verified":true}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	v, err := c.SendVerification(icheck.VerificationEmail, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Channel != icheck.VerificationEmail || v.Destination != "n***@icheck.vn" {
+		t.Errorf("verification = %+v", v)
+	}
+
+	user, err := c.ConfirmVerification("123456", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 7 || !user.EmailVerified {
+		t.Errorf("user = %+v", user)
+	}
+}
+
+func TestResendVerificationCooldown(t *testing.T) {
+	resendAt := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"status":200,"data":{"channel":"phone","resend_at":"` + resendAt.Format(time.RFC3339) + `"}}`))
+	})
+
+	if _, err := c.SendVerification(icheck.VerificationPhone, &icheck.Params{AccessToken: "t"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.ResendVerification(icheck.VerificationPhone, &icheck.Params{AccessToken: "t"})
+	cooldown, ok := err.(*icheck.ErrResendCooldown)
+	if !ok {
+		t.Fatalf("got %v, want *icheck.ErrResendCooldown", err)
+	}
+	if !cooldown.RetryAt.Equal(resendAt) {
+		t.Errorf("RetryAt = %v, want %v", cooldown.RetryAt, resendAt)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+
+	// The cooldown is kept per channel and access token.
+	if _, err := c.ResendVerification(icheck.VerificationEmail, &icheck.Params{AccessToken: "t"}); err != nil {
+		t.Errorf("email resend: %v", err)
+	}
+}
+
+func TestResendVerificationForgetsPastCooldowns(t *testing.T) {
+	var resendAt time.Time
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":200,"data":{"channel":"phone","resend_at":"` + resendAt.Format(time.RFC3339Nano) + `"}}`))
+	})
+
+	resendAt = time.Now().Add(-time.Second)
+	if _, err := c.SendVerification(icheck.VerificationPhone, &icheck.Params{AccessToken: "old-token"}); err != nil {
+		t.Fatal(err)
+	}
+	for key := range c.resendAt {
+		if strings.Contains(key, "old-token") {
+			t.Errorf("cooldown key %q holds the access token", key)
+		}
+	}
+
+	resendAt = time.Now().Add(time.Minute)
+	if _, err := c.SendVerification(icheck.VerificationPhone, &icheck.Params{AccessToken: "new-token"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.resendAt) != 1 {
+		t.Errorf("%d cooldowns kept, want 1", len(c.resendAt))
+	}
+
+	// A cooldown that has passed is removed when resending.
+	c.resendAt[cooldownKey(icheck.VerificationPhone, &icheck.Params{AccessToken: "new-token"})] = time.Now().Add(-time.Second)
+	resendAt = time.Time{}
+	if _, err := c.ResendVerification(icheck.VerificationPhone, &icheck.Params{AccessToken: "new-token"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.resendAt) != 0 {
+		t.Errorf("%d cooldowns kept, want 0", len(c.resendAt))
+	}
+}
@@ -2,30 +2,36 @@ package icheck
 
 // AccountKitLoginParams ...
 type AccountKitLoginParams struct {
-	Code     string
-	Name     string
-	Password string
-	TTL      int64
+	Code     string `form:"code,omitempty"`
+	Name     string `form:"name,omitempty"`
+	Password string `form:"password,omitempty"`
+	TTL      int64  `form:"ttl,omitempty"`
 }
 
 // AccountKitResetPasswordParams ...
 type AccountKitResetPasswordParams struct {
-	Code     string
-	Password string
+	Code     string `form:"code,omitempty"`
+	Password string `form:"password,omitempty"`
 }
 
-// AccountKitResetPasswordResponse ...
+// AccountKitResetPasswordResponse holds the user whose password was reset.
 type AccountKitResetPasswordResponse struct {
-	Data interface{}
+	Data *User `json:"data"`
 }
 
 // AccountKitChangePhoneParams ...
 type AccountKitChangePhoneParams struct {
-	Code     string
-	Password string
+	Code     string `form:"code,omitempty"`
+	Password string `form:"password,omitempty"`
+}
+
+// PhoneChange is the result of changing the phone number of a user.
+type PhoneChange struct {
+	Phone         string `json:"phone"`
+	PhoneVerified bool   `json:"phone_verified"`
 }
 
 // AccountKitChangePhoneResponse ...
 type AccountKitChangePhoneResponse struct {
-	Data interface{}
+	Data *PhoneChange `json:"data"`
 }
@@ -1,8 +1,10 @@
+// Package accountkit logs users in with Facebook Account Kit.
+//
+// Deprecated: Account Kit has been shut down. Use the otp package;
+// otp.AccountKitClient has the methods of Client.
 package accountkit
 
 import (
-	"strconv"
-
 	icheck "github.com/icheckteam/icheck-go"
 )
 
@@ -12,47 +14,29 @@ type Client struct {
 }
 
 func (c *Client) Login(params *icheck.AccountKitLoginParams) (*icheck.AccessToken, error) {
-	body := &icheck.RequestValues{}
-
-	if params.Code != "" {
-		body.Add("code", params.Code)
-	}
-
-	if params.Name != "" {
-		body.Add("name", params.Name)
-	}
-
-	if params.Password != "" {
-		body.Add("password", params.Password)
-	}
-
-	if params.TTL > 0 {
-		body.Add("ttl", strconv.FormatInt(params.TTL, 10))
+	body, err := icheck.EncodeForm(params)
+	if err != nil {
+		return nil, err
 	}
 
 	resp := &icheck.LoginResponse{}
 
-	err := c.B.Call("POST", "/accountkit/login", body, nil, resp)
+	err = c.B.Call("POST", "/accountkit/login", body, nil, resp)
 	if err != nil {
 		return nil, err
 	}
-	return resp.Data, nil
+	return resp.Token()
 }
 
 func (c *Client) ResetPassword(params *icheck.AccountKitResetPasswordParams) (*icheck.AccountKitResetPasswordResponse, error) {
-	body := &icheck.RequestValues{}
-
-	if params.Code != "" {
-		body.Add("code", params.Code)
-	}
-
-	if params.Password != "" {
-		body.Add("password", params.Password)
+	body, err := icheck.EncodeForm(params)
+	if err != nil {
+		return nil, err
 	}
 
 	resp := &icheck.AccountKitResetPasswordResponse{}
 
-	err := c.B.Call("POST", "/accountkit/reset-password", body, nil, resp)
+	err = c.B.Call("POST", "/accountkit/reset-password", body, nil, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -60,19 +44,14 @@ func (c *Client) ResetPassword(params *icheck.AccountKitResetPasswordParams) (*i
 }
 
 func (c *Client) ChangePhone(data *icheck.AccountKitChangePhoneParams, params *icheck.Params) (*icheck.AccountKitChangePhoneResponse, error) {
-	body := &icheck.RequestValues{}
-
-	if data.Code != "" {
-		body.Add("code", data.Code)
-	}
-
-	if data.Password != "" {
-		body.Add("password", data.Password)
+	body, err := icheck.EncodeForm(data)
+	if err != nil {
+		return nil, err
 	}
 
 	resp := &icheck.AccountKitChangePhoneResponse{}
 
-	err := c.B.Call("POST", "/accountkit/change-phone", body, params, resp)
+	err = c.B.Call("POST", "/accountkit/change-phone", body, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,43 @@
+package accountkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestChangePhoneAndResetPassword(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if code := r.PostFormValue("code"); code != "ak-code" {
+			t.Errorf("code = %q", code)
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "POST /accountkit/change-phone":
+			w.Write([]byte(`{"status":200,"data":{"phone":"0977465849","phone_verified":true}}`))
+		case "POST /accountkit/reset-password":
+			w.Write([]byte(`{"status":200,"data":{"id":7,"phone":"0977465849"}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{B: &icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}}
+
+	changed, err := c.ChangePhone(&icheck.AccountKitChangePhoneParams{Code: "ak-code", Password: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.Data == nil || changed.Data.Phone != "0977465849" || !changed.Data.PhoneVerified {
+		t.Errorf("change = %+v", changed.Data)
+	}
+
+	reset, err := c.ResetPassword(&icheck.AccountKitResetPasswordParams{Code: "ak-code", Password: "n3w-Secret!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reset.Data == nil || reset.Data.ID != 7 {
+		t.Errorf("user = %+v", reset.Data)
+	}
+}
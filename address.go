@@ -17,8 +17,8 @@ type AddressResp struct {
 }
 
 type AddressBody struct {
-	Address  string `json:"address"`
-	City     int64  `json:"city"`
-	District int64  `json:"district"`
-	Email    string `json:"email"`
+	Address  string `json:"address" form:"address,omitempty"`
+	City     int64  `json:"city" form:"city,omitempty"`
+	District int64  `json:"district" form:"district,omitempty"`
+	Email    string `json:"email" form:"email,omitempty"`
 }
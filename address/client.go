@@ -2,7 +2,6 @@ package address
 
 import (
 	"fmt"
-	"strconv"
 
 	icheck "github.com/icheckteam/icheck-go"
 )
@@ -14,41 +13,40 @@ type Client struct {
 
 // List list all addresses
 func (c *Client) List(params *icheck.Params) (*icheck.AddressListResp, error) {
-	resp := &icheck.AddressListResp{}
-	err := c.B.Call("GET", "/addresses", nil, params, resp)
+	env, err := c.ListEnvelope(params)
 	if err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &icheck.AddressListResp{Data: env.Data}, nil
+}
+
+// ListEnvelope is List returning the whole response envelope.
+func (c *Client) ListEnvelope(params *icheck.Params) (*icheck.Envelope[[]icheck.Address], error) {
+	return icheck.CallEnvelope[[]icheck.Address](c.B, "GET", "/addresses", nil, params)
 }
 
 // Get get address detail
 func (c *Client) Get(id string, params *icheck.Params) (*icheck.AddressResp, error) {
-	resp := &icheck.AddressResp{}
-	err := c.B.Call("GET", fmt.Sprintf("/addresses/%v", id), nil, params, resp)
+	env, err := c.GetEnvelope(id, params)
 	if err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &icheck.AddressResp{Data: env.Data}, nil
+}
+
+// GetEnvelope is Get returning the whole response envelope.
+func (c *Client) GetEnvelope(id string, params *icheck.Params) (*icheck.Envelope[icheck.Address], error) {
+	return icheck.CallEnvelope[icheck.Address](c.B, "GET", fmt.Sprintf("/addresses/%v", id), nil, params)
 }
 
 // Create create an address
 func (c *Client) Create(conf *icheck.AddressBody, params *icheck.Params) (*icheck.AddressResp, error) {
-	body := &icheck.RequestValues{}
-	if conf.Address != "" {
-		body.Add("address", conf.Address)
-	}
-	if conf.City != 0 {
-		body.Add("city", strconv.FormatInt(conf.City, 10))
-	}
-	if conf.District != 0 {
-		body.Add("district", strconv.FormatInt(conf.District, 10))
-	}
-	if conf.Email != "" {
-		body.Add("email", conf.Email)
+	body, err := icheck.EncodeForm(conf)
+	if err != nil {
+		return nil, err
 	}
 	resp := &icheck.AddressResp{}
-	err := c.B.Call("POST", fmt.Sprintf("/addresses"), body, params, resp)
+	err = c.B.Call("POST", fmt.Sprintf("/addresses"), body, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -57,21 +55,12 @@ func (c *Client) Create(conf *icheck.AddressBody, params *icheck.Params) (*ichec
 
 // Update update an address
 func (c *Client) Update(id string, conf *icheck.AddressBody, params *icheck.Params) (*icheck.AddressResp, error) {
-	body := &icheck.RequestValues{}
-	if conf.Address != "" {
-		body.Add("address", conf.Address)
-	}
-	if conf.City != 0 {
-		body.Add("city", strconv.FormatInt(conf.City, 10))
-	}
-	if conf.District != 0 {
-		body.Add("district", strconv.FormatInt(conf.District, 10))
-	}
-	if conf.Email != "" {
-		body.Add("email", conf.Email)
+	body, err := icheck.EncodeForm(conf)
+	if err != nil {
+		return nil, err
 	}
 	resp := &icheck.AddressResp{}
-	err := c.B.Call("PUT", fmt.Sprintf("/addresses/%v", id), body, params, resp)
+	err = c.B.Call("PUT", fmt.Sprintf("/addresses/%v", id), body, params, resp)
 	if err != nil {
 		return nil, err
 	}
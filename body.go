@@ -0,0 +1,123 @@
+package icheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"sync/atomic"
+)
+
+// RequestBody is a request body sent instead of the usual URL encoded form.
+// Set it on Params.Body to pick the encoding of a single call; the form
+// given to Call is then sent in the query string.
+type RequestBody interface {
+	// Encode returns the content type and the content of the body.
+	Encode() (contentType string, body io.Reader, err error)
+}
+
+// ErrBodyConsumed is returned when a body that can only be sent once is
+// encoded again, e.g. by a retry.
+var ErrBodyConsumed = errors.New("icheck: request body already sent")
+
+// Replayable reports whether body can be encoded more than once, so that a
+// call sending it can be retried. MultipartBody is not, as its files are
+// streams.
+func Replayable(body RequestBody) bool {
+	_, stream := body.(*MultipartBody)
+	return !stream
+}
+
+// JSONBody sends V encoded as JSON, following its json struct tags.
+type JSONBody struct {
+	V interface{}
+}
+
+// Encode is the RequestBody.Encode implementation.
+func (b *JSONBody) Encode() (string, io.Reader, error) {
+	data, err := json.Marshal(b.V)
+	if err != nil {
+		return "", nil, err
+	}
+	return "application/json", bytes.NewReader(data), nil
+}
+
+// FilePart is a file sent in a multipart/form-data body.
+type FilePart struct {
+	// FieldName is the name of the form field, e.g. "avatar".
+	FieldName string
+	// FileName is the name of the file reported to the API.
+	FileName string
+	// ContentType defaults to application/octet-stream.
+	ContentType string
+	// Reader provides the content of the file. It is read once, while the
+	// request is sent.
+	Reader io.Reader
+}
+
+// MultipartBody sends Fields and Files as multipart/form-data. Files are
+// streamed to the API rather than loaded in memory. Since each file is read
+// once, a MultipartBody cannot be sent twice: encoding it again fails with
+// ErrBodyConsumed.
+type MultipartBody struct {
+	Fields *RequestValues
+	Files  []FilePart
+
+	encoded atomic.Bool
+}
+
+// Encode is the RequestBody.Encode implementation.
+func (b *MultipartBody) Encode() (string, io.Reader, error) {
+	if b.encoded.Swap(true) {
+		return "", nil, ErrBodyConsumed
+	}
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		pw.CloseWithError(b.write(mw))
+	}()
+
+	return mw.FormDataContentType(), pr, nil
+}
+
+func (b *MultipartBody) write(mw *multipart.Writer) error {
+	if b.Fields != nil {
+		for _, v := range b.Fields.values {
+			if err := mw.WriteField(v.Key, v.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, f := range b.Files {
+		contentType := f.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(f.FieldName), escapeQuotes(f.FileName)))
+		h.Set("Content-Type", contentType)
+
+		part, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, f.Reader); err != nil {
+			return err
+		}
+	}
+
+	return mw.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,87 @@
+package icheck
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCallWithJSONBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if q := r.URL.Query().Get("ttl"); q != "60" {
+			t.Errorf("ttl = %q", q)
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		w.Write([]byte(`{"status":200,"data":` + string(data) + `}`))
+	}))
+	defer srv.Close()
+
+	form := &RequestValues{}
+	form.Add("ttl", "60")
+	b := &BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}
+	resp := &AddressResp{}
+	err := b.Call("POST", "/addresses", form, &Params{Body: &JSONBody{V: &AddressBody{Address: "1 Trang Tien", City: 1}}}, resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.Address != "1 Trang Tien" || resp.Data.City != 1 {
+		t.Errorf("got %+v", resp.Data)
+	}
+}
+
+func TestCallWithMultipartBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+			return
+		}
+		f, h, err := r.FormFile("avatar")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		data, _ := ioutil.ReadAll(f)
+		out, _ := json.Marshal(map[string]interface{}{
+			"status": 200,
+			"data":   map[string]string{"social_name": r.FormValue("name"), "avatar": h.Filename + ":" + string(data)},
+		})
+		w.Write(out)
+	}))
+	defer srv.Close()
+
+	fields := &RequestValues{}
+	fields.Add("name", "Lan")
+	b := &BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}
+	resp := &UserResponse{}
+	err := b.Call("POST", "/account", nil, &Params{Body: &MultipartBody{
+		Fields: fields,
+		Files:  []FilePart{{FieldName: "avatar", FileName: "me.png", Reader: strings.NewReader("png")}},
+	}}, resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.User.Name != "Lan" || resp.User.Avatar != "me.png:png" {
+		t.Errorf("got %+v", resp.User)
+	}
+}
+
+func TestMultipartBodyEncodesOnce(t *testing.T) {
+	body := &MultipartBody{Files: []FilePart{{FieldName: "avatar", FileName: "a.png", Reader: strings.NewReader("PNGDATA")}}}
+	_, r, err := body.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.ReadAll(r)
+	if _, _, err := body.Encode(); err != ErrBodyConsumed {
+		t.Errorf("second Encode: err = %v, want ErrBodyConsumed", err)
+	}
+	if Replayable(body) || !Replayable(&JSONBody{}) {
+		t.Error("Replayable is wrong")
+	}
+}
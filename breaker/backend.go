@@ -0,0 +1,300 @@
+// Package breaker provides an icheck.Backend that stops calling the iCheck
+// API while it is failing, so callers fail fast instead of piling up.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed lets every call through.
+	StateClosed State = iota
+	// StateOpen rejects every call with ErrCircuitOpen.
+	StateOpen
+	// StateHalfOpen lets a few trial calls through to probe the API.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// ErrCircuitOpen is returned instead of calling the API while the circuit is
+// open.
+type ErrCircuitOpen struct {
+	// Until is when the circuit lets trial calls through again.
+	Until time.Time
+}
+
+func (e *ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("icheck: circuit open until %s", e.Until.Format(time.RFC3339))
+}
+
+// Config configures a Backend. Zero fields take the documented defaults.
+type Config struct {
+	// Window is the period over which the failure rate is computed.
+	// Defaults to one minute.
+	Window time.Duration
+	// MinRequests is the number of calls needed in Window before the
+	// circuit may open. Defaults to 10.
+	MinRequests int
+	// FailureRate is the ratio of failed calls in Window that opens the
+	// circuit. Defaults to 0.5.
+	FailureRate float64
+	// Cooldown is how long the circuit stays open before trial calls are
+	// let through. Defaults to 30 seconds.
+	Cooldown time.Duration
+	// HalfOpenRequests is the number of trial calls let through at once
+	// while half-open. Defaults to 1.
+	HalfOpenRequests int
+	// IsFailure reports whether the outcome of a call counts as a failure.
+	// info describes this call alone; its Attempts is zero when the call
+	// failed before any request was sent. By default network errors of
+	// requests that were sent, and 5xx responses, count.
+	IsFailure func(info *icheck.CallInfo, err error) bool
+	// OnStateChange, if set, is called after every state transition. It
+	// runs with the breaker locked and must not call the Backend.
+	OnStateChange func(from, to State)
+}
+
+const windowBuckets = 10
+
+type bucket struct {
+	start    time.Time
+	total    int
+	failures int
+}
+
+// Backend wraps an icheck.Backend with a circuit breaker.
+type Backend struct {
+	B icheck.Backend
+
+	conf Config
+	now  func() time.Time
+
+	mu      sync.Mutex
+	state   State
+	until   time.Time
+	trials  int
+	buckets [windowBuckets]bucket
+}
+
+// NewBackend returns a Backend that guards calls made through b.
+func NewBackend(b icheck.Backend, conf *Config) *Backend {
+	c := Config{}
+	if conf != nil {
+		c = *conf
+	}
+	if c.Window <= 0 {
+		c.Window = time.Minute
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = 10
+	}
+	if c.FailureRate <= 0 {
+		c.FailureRate = 0.5
+	}
+	if c.Cooldown <= 0 {
+		c.Cooldown = 30 * time.Second
+	}
+	if c.HalfOpenRequests <= 0 {
+		c.HalfOpenRequests = 1
+	}
+	if c.IsFailure == nil {
+		c.IsFailure = isFailure
+	}
+	return &Backend{B: b, conf: c, now: time.Now}
+}
+
+// State returns the current state of the circuit.
+func (b *Backend) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(b.now())
+	return b.state
+}
+
+// Call is the Backend.Call implementation. It returns *ErrCircuitOpen
+// without calling the API while the circuit is open.
+func (b *Backend) Call(method, path string, form *icheck.RequestValues, params *icheck.Params, v interface{}) error {
+	trial, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	p := icheck.Params{}
+	if params != nil {
+		p = *params
+	}
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	info := &icheck.CallInfo{}
+	if outer := icheck.CallInfoFromContext(ctx); outer != nil {
+		info = outer
+	} else {
+		ctx = icheck.WithCallInfo(ctx, info)
+	}
+	p.Context = ctx
+	info.StatusCode = 0
+	before := info.Attempts
+
+	err = b.B.Call(method, path, form, &p, v)
+	call := *info
+	call.Attempts -= before
+	if err != nil && call.Attempts == 0 && call.StatusCode == 0 {
+		// The call failed before reaching the API, which says nothing of
+		// its health.
+		b.release(trial)
+		return err
+	}
+	b.record(trial, b.conf.IsFailure(&call, err))
+	return err
+}
+
+// allow reports whether a call may go through and whether it is a trial.
+func (b *Backend) allow() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(b.now())
+	switch b.state {
+	case StateOpen:
+		return false, &ErrCircuitOpen{Until: b.until}
+	case StateHalfOpen:
+		if b.trials >= b.conf.HalfOpenRequests {
+			return false, &ErrCircuitOpen{Until: b.until}
+		}
+		b.trials++
+		return true, nil
+	}
+	return false, nil
+}
+
+// release gives back the slot of a trial call that did not reach the API.
+func (b *Backend) release(trial bool) {
+	if !trial {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.trials > 0 {
+		b.trials--
+	}
+}
+
+func (b *Backend) record(trial, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if trial {
+		if b.trials > 0 {
+			b.trials--
+		}
+		if b.state != StateHalfOpen {
+			return
+		}
+		if failed {
+			b.open(now)
+		} else {
+			b.reset()
+			b.transition(StateClosed)
+		}
+		return
+	}
+	if b.state != StateClosed {
+		return
+	}
+
+	bk := b.bucket(now)
+	bk.total++
+	if failed {
+		bk.failures++
+	}
+
+	total, failures := 0, 0
+	for _, bk := range b.buckets {
+		if now.Sub(bk.start) < b.conf.Window {
+			total += bk.total
+			failures += bk.failures
+		}
+	}
+	if total >= b.conf.MinRequests && float64(failures) >= b.conf.FailureRate*float64(total) {
+		b.open(now)
+	}
+}
+
+// bucket returns the bucket counting calls made at now.
+func (b *Backend) bucket(now time.Time) *bucket {
+	width := b.conf.Window / windowBuckets
+	start := now.Truncate(width)
+	bk := &b.buckets[int(start.UnixNano()/int64(width))%windowBuckets]
+	if !bk.start.Equal(start) {
+		*bk = bucket{start: start}
+	}
+	return bk
+}
+
+// advance moves an open circuit to half-open once its cooldown has passed.
+func (b *Backend) advance(now time.Time) {
+	if b.state == StateOpen && !now.Before(b.until) {
+		b.trials = 0
+		b.transition(StateHalfOpen)
+	}
+}
+
+func (b *Backend) open(now time.Time) {
+	b.until = now.Add(b.conf.Cooldown)
+	b.reset()
+	b.transition(StateOpen)
+}
+
+func (b *Backend) reset() {
+	b.buckets = [windowBuckets]bucket{}
+}
+
+func (b *Backend) transition(to State) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	logrus.Debugf("Icheck circuit breaker: %s -> %s\n", from, to)
+	if b.conf.OnStateChange != nil {
+		b.conf.OnStateChange(from, to)
+	}
+}
+
+// isFailure counts calls that got no response, apart from those canceled by
+// the caller or failing before any request was sent, and calls answered with
+// a 5xx status.
+func isFailure(info *icheck.CallInfo, err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if info.StatusCode == 0 && info.Attempts > 0 || info.StatusCode >= 500 {
+		return true
+	}
+	var apiErr *icheck.Error
+	return errors.As(err, &apiErr) && apiErr.Status >= 500
+}
@@ -0,0 +1,84 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+type backendFunc func(params *icheck.Params) error
+
+func (f backendFunc) Call(method, path string, form *icheck.RequestValues, params *icheck.Params, v interface{}) error {
+	return f(params)
+}
+
+func TestBackendStates(t *testing.T) {
+	status := 502
+	calls := 0
+	inner := backendFunc(func(params *icheck.Params) error {
+		calls++
+		icheck.CallInfoFromContext(params.Context).StatusCode = status
+		if status >= 500 {
+			return &icheck.Error{Status: status, Message: "bad gateway"}
+		}
+		return nil
+	})
+
+	now := time.Unix(1000, 0)
+	b := NewBackend(inner, &Config{MinRequests: 2, Cooldown: time.Minute})
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		b.Call("GET", "/account", nil, nil, nil)
+	}
+	if s := b.State(); s != StateOpen {
+		t.Fatalf("state = %v, want open", s)
+	}
+
+	err := b.Call("GET", "/account", nil, nil, nil)
+	var open *ErrCircuitOpen
+	if !errors.As(err, &open) || calls != 2 {
+		t.Fatalf("got %v after %d calls, want ErrCircuitOpen after 2", err, calls)
+	}
+
+	now = now.Add(time.Minute)
+	if s := b.State(); s != StateHalfOpen {
+		t.Fatalf("state = %v, want half-open", s)
+	}
+	status = 200
+	if err := b.Call("GET", "/account", nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if s := b.State(); s != StateClosed {
+		t.Fatalf("state = %v, want closed", s)
+	}
+}
+
+func TestBackendIgnoresErrorsBeforeSending(t *testing.T) {
+	sent := false
+	inner := backendFunc(func(params *icheck.Params) error {
+		if sent {
+			icheck.CallInfoFromContext(params.Context).Attempts++
+			return errors.New("connection refused")
+		}
+		return icheck.ErrInsecureToken
+	})
+	b := NewBackend(inner, &Config{MinRequests: 2, Cooldown: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		b.Call("GET", "/account", nil, nil, nil)
+	}
+	if s := b.State(); s != StateClosed {
+		t.Fatalf("state = %v after unsent calls, want closed", s)
+	}
+
+	sent = true
+	for i := 0; i < 2; i++ {
+		b.Call("GET", "/account", nil, nil, nil)
+	}
+	if s := b.State(); s != StateOpen {
+		t.Fatalf("state = %v after network errors, want open", s)
+	}
+}
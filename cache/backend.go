@@ -0,0 +1,197 @@
+// Package cache provides an icheck.Backend that caches the responses of GET
+// calls and revalidates them with ETags.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// DefaultTTLs is used when Config.TTLs is nil.
+var DefaultTTLs = map[string]time.Duration{
+	"/locations":      24 * time.Hour,
+	"/locations/{id}": 24 * time.Hour,
+	"/search":         time.Minute,
+	"/users/{id}":     5 * time.Minute,
+}
+
+// DefaultInvalidates is used when Config.Invalidates is nil.
+var DefaultInvalidates = map[string][]string{
+	"POST /account": {"users"},
+}
+
+// Config configures a Backend.
+type Config struct {
+	// Store holds the cached responses. Defaults to a MemoryStore of 1000
+	// entries.
+	Store Store
+	// TTLs maps route templates, as returned by icheck.RouteTemplate, to how
+	// long their responses stay fresh. Routes that are not listed are not
+	// cached.
+	TTLs map[string]time.Duration
+	// StaleTTL is how long a response carrying an ETag is kept after it
+	// stops being fresh, so it can be revalidated with If-None-Match.
+	// Defaults to one day.
+	StaleTTL time.Duration
+	// Invalidates maps a mutating call, written as the method and route
+	// template ("PUT /addresses/{id}"), to the groups it invalidates besides
+	// its own. A group is the first segment of a route, e.g. "addresses".
+	Invalidates map[string][]string
+}
+
+// Backend wraps an icheck.Backend and serves repeated GET calls from a
+// Store. Responses are cached per access token, so users never see each
+// other's data. Any successful call that is not a GET invalidates the
+// cached responses of its group.
+type Backend struct {
+	B icheck.Backend
+
+	conf Config
+	now  func() time.Time
+}
+
+type entry struct {
+	Body    json.RawMessage `json:"body"`
+	ETag    string          `json:"etag,omitempty"`
+	Expires time.Time       `json:"expires"`
+}
+
+// NewBackend returns a Backend that caches calls made through b.
+func NewBackend(b icheck.Backend, conf *Config) *Backend {
+	c := Config{}
+	if conf != nil {
+		c = *conf
+	}
+	if c.Store == nil {
+		c.Store = NewMemoryStore(0)
+	}
+	if c.TTLs == nil {
+		c.TTLs = DefaultTTLs
+	}
+	if c.StaleTTL <= 0 {
+		c.StaleTTL = 24 * time.Hour
+	}
+	if c.Invalidates == nil {
+		c.Invalidates = DefaultInvalidates
+	}
+	return &Backend{B: b, conf: c, now: time.Now}
+}
+
+// Invalidate drops every cached response of the given groups.
+func (b *Backend) Invalidate(groups ...string) {
+	for _, group := range groups {
+		logrus.Debugf("Invalidating cached Icheck responses of %s\n", group)
+		b.conf.Store.DeletePrefix(group + "|")
+	}
+}
+
+// Call is the Backend.Call implementation.
+func (b *Backend) Call(method, path string, form *icheck.RequestValues, params *icheck.Params, v interface{}) error {
+	route := icheck.RouteTemplate(path)
+	group := icheck.RouteGroup(path)
+
+	if strings.ToUpper(method) != "GET" {
+		if err := b.B.Call(method, path, form, params, v); err != nil {
+			return err
+		}
+		b.Invalidate(append([]string{group}, b.conf.Invalidates[strings.ToUpper(method)+" "+route]...)...)
+		return nil
+	}
+
+	ttl := b.conf.TTLs[route]
+	if ttl <= 0 {
+		return b.B.Call(method, path, form, params, v)
+	}
+
+	key := b.key(group, path, form, params)
+	var cached *entry
+	if data, ok := b.conf.Store.Get(key); ok {
+		e := &entry{}
+		if err := json.Unmarshal(data, e); err == nil {
+			cached = e
+		}
+	}
+	if cached != nil && b.now().Before(cached.Expires) {
+		return json.Unmarshal(cached.Body, v)
+	}
+
+	p := icheck.Params{}
+	if params != nil {
+		p = *params
+	}
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	info := icheck.CallInfoFromContext(ctx)
+	if info == nil {
+		info = &icheck.CallInfo{}
+		ctx = icheck.WithCallInfo(ctx, info)
+	}
+	p.Context = ctx
+	if cached != nil && cached.ETag != "" {
+		p.Headers = http.Header{}
+		if params != nil {
+			for k, lines := range params.Headers {
+				p.Headers[k] = lines
+			}
+		}
+		p.Headers.Set("If-None-Match", cached.ETag)
+	}
+
+	err := b.B.Call(method, path, form, &p, v)
+	if err == icheck.ErrNotModified && cached != nil {
+		cached.Expires = b.now().Add(ttl)
+		b.store(key, cached, ttl)
+		return json.Unmarshal(cached.Body, v)
+	}
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		logrus.Debugf("Cannot cache Icheck response: %v\n", err)
+		return nil
+	}
+	b.store(key, &entry{
+		Body:    body,
+		ETag:    info.Header.Get("ETag"),
+		Expires: b.now().Add(ttl),
+	}, ttl)
+	return nil
+}
+
+func (b *Backend) store(key string, e *entry, ttl time.Duration) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if e.ETag != "" {
+		ttl += b.conf.StaleTTL
+	}
+	b.conf.Store.Set(key, data, ttl)
+}
+
+// key identifies a GET call. It starts with the call's group so Invalidate
+// can drop a whole group, followed by a hash of the access token.
+func (b *Backend) key(group, path string, form *icheck.RequestValues, params *icheck.Params) string {
+	scope := "public"
+	if params != nil && params.AccessToken != "" {
+		sum := sha256.Sum256([]byte(params.AccessToken))
+		scope = hex.EncodeToString(sum[:16])
+	}
+	key := group + "|" + scope + "|GET " + path
+	if form != nil && !form.Empty() {
+		key += "?" + form.Encode()
+	}
+	return key
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestBackendRevalidatesAndInvalidates(t *testing.T) {
+	gets := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			gets++
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"status":200,"data":{"id":7,"social_name":"Lan"}}`))
+	}))
+	defer srv.Close()
+
+	b := NewBackend(&icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}, nil)
+	now := time.Unix(1000, 0)
+	b.now = func() time.Time { return now }
+
+	get := func() *icheck.User {
+		resp := &icheck.UserResponse{}
+		if err := b.Call("GET", "/users/7", nil, &icheck.Params{AccessToken: "t"}, resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.User
+	}
+
+	get()
+	if u := get(); gets != 1 || u.Name != "Lan" {
+		t.Fatalf("fresh hit: gets = %d, user = %+v", gets, u)
+	}
+
+	now = now.Add(time.Hour)
+	if u := get(); gets != 2 || u.Name != "Lan" {
+		t.Fatalf("revalidation: gets = %d, user = %+v", gets, u)
+	}
+
+	if err := b.Call("POST", "/account", nil, &icheck.Params{AccessToken: "t"}, &icheck.UserResponse{}); err != nil {
+		t.Fatal(err)
+	}
+	get()
+	if gets != 3 {
+		t.Fatalf("after invalidation: gets = %d, want 3", gets)
+	}
+}
+
+func TestMemoryStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	s := NewMemoryStore(2)
+	s.Set("a", []byte("1"), time.Minute)
+	s.Set("b", []byte("2"), time.Minute)
+	s.Get("a")
+	s.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := s.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if _, ok := s.Get("a"); !ok {
+		t.Error("a should have been kept")
+	}
+}
@@ -0,0 +1,39 @@
+package icheck
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// CallInfo records what happened on the wire while a Backend.Call was
+// executed. Attach one to Params.Context with WithCallInfo and the backend
+// fills it in as requests are made.
+type CallInfo struct {
+	// Attempts is the number of HTTP requests sent for the call. Anything
+	// above one means the call was retried.
+	Attempts int
+	// StatusCode is the HTTP status code of the last response, or zero if
+	// no response was received.
+	StatusCode int
+	// Header holds the headers of the last response.
+	Header http.Header
+	// Latency is the time spent waiting on the last response.
+	Latency time.Duration
+}
+
+type callInfoKey struct{}
+
+// WithCallInfo returns a copy of ctx which carries info.
+func WithCallInfo(ctx context.Context, info *CallInfo) context.Context {
+	return context.WithValue(ctx, callInfoKey{}, info)
+}
+
+// CallInfoFromContext returns the CallInfo attached to ctx, if any.
+func CallInfoFromContext(ctx context.Context) *CallInfo {
+	if ctx == nil {
+		return nil
+	}
+	info, _ := ctx.Value(callInfoKey{}).(*CallInfo)
+	return info
+}
@@ -1,11 +1,13 @@
 package icheck
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"mime"
 	"net/http"
 	"strings"
 	"time"
@@ -13,13 +15,22 @@ import (
 	"github.com/Sirupsen/logrus"
 )
 
-var apiURL = "https://core.icheck.com.vn"
-var apiDevURL = "http://sandbox.icheck.com.vn:4336"
+// ProductionURL and SandboxURL are the URLs of the iCheck APIs.
+const (
+	ProductionURL = "https://core.icheck.com.vn"
+	SandboxURL    = "http://sandbox.icheck.com.vn:4336"
+)
+
+var apiURL = ProductionURL
+var apiDevURL = SandboxURL
 var Dev = true
 
 var AppID string
 var Secret string
 
+// defaultHTTPTimeout is the timeout of the HTTP client created by GetBackend.
+const defaultHTTPTimeout = 30 * time.Second
+
 type Backend interface {
 	Call(method, path string, form *RequestValues, params *Params, v interface{}) error
 }
@@ -28,6 +39,12 @@ type Backend interface {
 type BackendConfiguration struct {
 	URL        string
 	HTTPClient *http.Client
+	// MaxBodySize is the largest response body accepted, in bytes. Defaults
+	// to DefaultMaxBodySize.
+	MaxBodySize int64
+	// AllowInsecureTokens lets access tokens be sent over plain HTTP, with
+	// a warning. It is set by GetBackend in dev mode, for the sandbox.
+	AllowInsecureTokens bool
 }
 
 func GetBackend() Backend {
@@ -35,27 +52,46 @@ func GetBackend() Backend {
 	if Dev == true {
 		api = apiDevURL
 	}
+	httpClient, err := NewHTTPClient(nil)
+	if err != nil {
+		// The default configuration has nothing that can fail.
+		panic(err)
+	}
 	return &BackendConfiguration{
-		URL:        api,
-		HTTPClient: &http.Client{},
+		URL:                 api,
+		HTTPClient:          httpClient,
+		AllowInsecureTokens: Dev,
 	}
 }
 
 // Call is the Backend.Call implementation for invoking Icheck APIs.
 func (s BackendConfiguration) Call(method, path string, form *RequestValues, params *Params, v interface{}) error {
 	var body io.Reader
+	contentType := "application/x-www-form-urlencoded"
 	if form != nil && !form.Empty() {
 		logrus.Debugf("method: %s, path: %s, data: %v\n", method, path, form)
 		data := form.Encode()
-		if strings.ToUpper(method) == "GET" {
+		if strings.ToUpper(method) == "GET" || (params != nil && params.Body != nil) {
 			path += "?" + data
 		} else {
 			body = bytes.NewBufferString(data)
 		}
 	}
 
-	req, err := s.NewRequest(method, path, "application/x-www-form-urlencoded", body, params)
+	if params != nil && params.Body != nil {
+		var err error
+		contentType, body, err = params.Body.Encode()
+		if err != nil {
+			logrus.Debugf("Cannot encode Icheck request body: %v\n", err)
+			return err
+		}
+	}
+
+	req, err := s.NewRequest(method, path, contentType, body, params)
 	if err != nil {
+		if c, ok := body.(io.Closer); ok {
+			c.Close()
+		}
 		return err
 	}
 
@@ -83,9 +119,16 @@ func (s *BackendConfiguration) NewRequest(method, path, contentType string, body
 
 	req.SetBasicAuth("icheck", "iYAF&;cBe#G3a~D:#heck")
 	req.Header.Add("Content-Type", contentType)
+	req.Header.Add("Accept-Encoding", "gzip, deflate")
 
 	if params != nil {
+		if params.Context != nil {
+			req = req.WithContext(params.Context)
+		}
 		if params.AccessToken != "" {
+			if err := checkTokenTransport(req.URL, s.AllowInsecureTokens); err != nil {
+				return nil, err
+			}
 			req.Header.Add("access-token", params.AccessToken)
 		}
 		for k, v := range params.Headers {
@@ -103,8 +146,9 @@ type Response struct {
 }
 
 // Do is used by Call to execute an API request and parse the response. It uses
-// the backend's HTTP client to execute the request and unmarshals the response
-// into v. It also handles unmarshaling errors returned by the API.
+// the backend's HTTP client to execute the request and decodes the response
+// into v as it is read, decompressing it if needed. It also handles
+// unmarshaling errors returned by the API.
 func (s *BackendConfiguration) Do(req *http.Request, v interface{}) error {
 	logrus.Debugf("Requesting %v %v%v\n", req.Method, req.URL.Host, req.URL.Path)
 
@@ -112,7 +156,19 @@ func (s *BackendConfiguration) Do(req *http.Request, v interface{}) error {
 
 	res, err := s.HTTPClient.Do(req)
 
-	logrus.Debugf("Completed in %v\n", time.Since(start))
+	elapsed := time.Since(start)
+	logrus.Debugf("Completed in %v\n", elapsed)
+
+	if info := CallInfoFromContext(req.Context()); info != nil {
+		info.Attempts++
+		info.Latency = elapsed
+		info.StatusCode = 0
+		info.Header = nil
+		if res != nil {
+			info.StatusCode = res.StatusCode
+			info.Header = res.Header
+		}
+	}
 
 	if err != nil {
 		logrus.Debugf("Request to Icheck failed: %v\n", err)
@@ -120,37 +176,110 @@ func (s *BackendConfiguration) Do(req *http.Request, v interface{}) error {
 	}
 	defer res.Body.Close()
 
-	resBody, err := ioutil.ReadAll(res.Body)
+	if res.StatusCode == http.StatusNotModified {
+		return ErrNotModified
+	}
+
+	body, err := responseBody(res, s.MaxBodySize)
 	if err != nil {
-		logrus.Debugf("Cannot parse Icheck response: %v\n", err)
+		logrus.Debugf("Cannot read Icheck response: %v\n", err)
+		return err
+	}
+	if logrus.GetLevel() >= logrus.DebugLevel {
+		logged := &bytes.Buffer{}
+		body = io.TeeReader(body, logged)
+		defer func() {
+			logrus.Debugf("Icheck Response: %q\n", logged.Bytes())
+		}()
+	}
+
+	br := bufio.NewReader(body)
+	if _, err := br.Peek(1); err == io.EOF {
+		// Empty bodies are expected on 204 No Content, e.g. when deleting.
+		if res.StatusCode >= 400 {
+			return &Error{Status: res.StatusCode, Message: http.StatusText(res.StatusCode)}
+		}
+		return nil
+	} else if err != nil {
+		logrus.Debugf("Cannot read Icheck response: %v\n", err)
 		return err
 	}
-	logrus.Debugf("Icheck Response: %q\n", resBody)
-	resData := &Response{}
-	if err := json.Unmarshal(resBody, resData); err != nil {
+
+	// Some servers mislabel JSON, so the content type is only trusted when
+	// the body does not look like a JSON object either.
+	if contentType := res.Header.Get("Content-Type"); !isJSON(contentType) && !looksLikeJSON(br) {
+		snippet := make([]byte, 256)
+		n, _ := io.ReadFull(br, snippet)
+		return &ErrUnexpectedResponse{
+			StatusCode:  res.StatusCode,
+			ContentType: contentType,
+			Body:        string(snippet[:n]),
+		}
+	}
+
+	// When the HTTP status reports an error, nothing is decoded into v and
+	// the whole envelope is kept to build the error.
+	target := v
+	if res.StatusCode >= 400 {
+		target = &struct{}{}
+	}
+	status, rest, err := decodeResponse(br, target)
+	if err != nil {
 		logrus.Debugf("Cannot parse Icheck response: %v\n", err)
 		return err
 	}
-	if resData.Status >= 400 {
-		if resData.Status == 400 {
+	if status == 0 || res.StatusCode >= 400 && status < 400 {
+		status = res.StatusCode
+	}
+	if status >= 400 {
+		resBody, err := json.Marshal(rest)
+		if err != nil {
+			return err
+		}
+		if status == 400 {
 			badRequest := &ErrBadRequest{}
 			if err := json.Unmarshal(resBody, badRequest); err != nil {
 				logrus.Debugf("Cannot parse Icheck response: %v\n", err)
 				return err
 			}
+			badRequest.Status = status
 			return badRequest
 		}
 
-		return s.ResponseToError(res, resBody)
-	}
-
-	if err := json.Unmarshal(resBody, v); err != nil {
-		logrus.Debugf("Cannot parse Icheck response: %v\n", err)
+		err = s.ResponseToError(res, resBody)
+		if apiErr, ok := err.(*Error); ok {
+			apiErr.Status = status
+			if apiErr.Message == "" {
+				apiErr.Message = http.StatusText(status)
+			}
+		}
 		return err
 	}
+
 	return nil
 }
 
+// looksLikeJSON reports whether the body buffered in br starts with a JSON
+// object.
+func looksLikeJSON(br *bufio.Reader) bool {
+	head, _ := br.Peek(64)
+	head = bytes.TrimLeft(head, " \t\r\n")
+	return len(head) > 0 && head[0] == '{'
+}
+
+// isJSON reports whether contentType announces a JSON body. A missing
+// content type is given the benefit of the doubt.
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
 func (s *BackendConfiguration) ResponseToError(res *http.Response, resBody []byte) error {
 	// for some odd reason, the Erro structure doesn't unmarshal
 	// initially I thought it was because it's a struct inside of a struct
@@ -162,6 +291,10 @@ func (s *BackendConfiguration) ResponseToError(res *http.Response, resBody []byt
 	return err
 }
 
+// ErrNotModified is returned by Do when the API answers 304 Not Modified to a
+// conditional request. v is left untouched.
+var ErrNotModified = errors.New("icheck: not modified")
+
 // Error invalid
 type ErrBadRequest struct {
 	Status            int
@@ -190,6 +323,19 @@ type Error struct {
 	Message string
 }
 
+// ErrUnexpectedResponse is returned when the API answers with something other
+// than a JSON envelope, such as the HTML error page of a proxy.
+type ErrUnexpectedResponse struct {
+	StatusCode  int
+	ContentType string
+	// Body holds the beginning of the response body.
+	Body string
+}
+
+func (e *ErrUnexpectedResponse) Error() string {
+	return fmt.Sprintf("icheck: unexpected %q response with HTTP status %d: %q", e.ContentType, e.StatusCode, e.Body)
+}
+
 // Error serializes the error object to JSON and returns it as a string.
 func (e *Error) Error() string {
 	return e.Message
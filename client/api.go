@@ -6,18 +6,30 @@ import (
 	"github.com/icheckteam/icheck-go/accountkit"
 	"github.com/icheckteam/icheck-go/address"
 	"github.com/icheckteam/icheck-go/location"
+	"github.com/icheckteam/icheck-go/otp"
 	"github.com/icheckteam/icheck-go/search"
 	"github.com/icheckteam/icheck-go/user"
 )
 
 // API is the Icheck client. It contains all the different resources available.
 type API struct {
-	Account    *account.Client
-	User       *user.Client
-	Search     *search.Client
-	Location   *location.Client
+	Account  *account.Client
+	User     *user.Client
+	Search   *search.Client
+	Location *location.Client
+	// Deprecated: Account Kit has been shut down; use OTP.
 	AccountKit *accountkit.Client
+	OTP        *otp.Client
 	Address    *address.Client
+
+	// Environment is set on clients created by FromEnv or
+	// NewWithEnvironment.
+	Environment *Environment
+	// AppID and Secret are the credentials of the app, e.g. to check
+	// webhook events with webhook.ConstructEvent. New uses icheck.AppID and
+	// icheck.Secret.
+	AppID  string
+	Secret string
 }
 
 // Init initializes the Icheck client with the appropriate secret key
@@ -32,12 +44,13 @@ func (a *API) Init(backend icheck.Backend) {
 	a.Search = &search.Client{B: backend}
 	a.Location = &location.Client{B: backend}
 	a.AccountKit = &accountkit.Client{B: backend}
+	a.OTP = &otp.Client{B: backend, Provider: &otp.SMSProvider{B: backend}}
 	a.Address = &address.Client{B: backend}
 }
 
 // New Api .....
 func New() *API {
-	api := &API{}
+	api := &API{AppID: icheck.AppID, Secret: icheck.Secret}
 	api.Init(nil)
 	return api
 }
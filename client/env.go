@@ -0,0 +1,312 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// Names of the built-in environments.
+const (
+	Sandbox    = "sandbox"
+	Production = "production"
+)
+
+// Environment is a named iCheck deployment and the credentials of the app
+// using it.
+type Environment struct {
+	Name      string   `json:"-" yaml:"-" toml:"-"`
+	BaseURL   string   `json:"base_url" yaml:"base_url" toml:"base_url"`
+	AppID     string   `json:"app_id" yaml:"app_id" toml:"app_id"`
+	AppSecret string   `json:"app_secret" yaml:"app_secret" toml:"app_secret"`
+	Timeout   Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
+	// Proxy, CAFile and Pins are passed to icheck.TransportConfig.
+	Proxy  string   `json:"proxy" yaml:"proxy" toml:"proxy"`
+	CAFile string   `json:"ca_file" yaml:"ca_file" toml:"ca_file"`
+	Pins   []string `json:"pins" yaml:"pins" toml:"pins"`
+}
+
+// Duration is a time.Duration written as a string such as "30s" in
+// configuration files.
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Config lists the environments an app may use.
+type Config struct {
+	// Default is the environment used when ICHECK_ENV is not set.
+	Default      string                  `json:"default" yaml:"default" toml:"default"`
+	Environments map[string]*Environment `json:"environments" yaml:"environments" toml:"environments"`
+}
+
+// DefaultConfig returns a Config holding the sandbox and production
+// environments. The default follows icheck.Dev.
+func DefaultConfig() *Config {
+	def := Production
+	if icheck.Dev {
+		def = Sandbox
+	}
+	return &Config{
+		Default: def,
+		Environments: map[string]*Environment{
+			Sandbox:    {Name: Sandbox, BaseURL: icheck.SandboxURL},
+			Production: {Name: Production, BaseURL: icheck.ProductionURL},
+		},
+	}
+}
+
+// LoadConfig reads the environments of the YAML, TOML or JSON file at path,
+// chosen by its extension, on top of DefaultConfig. Fields missing from an
+// environment of the file keep their default value.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := &Config{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, file)
+	case ".toml":
+		err = toml.Unmarshal(data, file)
+	case ".json":
+		err = json.Unmarshal(data, file)
+	default:
+		return nil, fmt.Errorf("icheck: unsupported config file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("icheck: %s: %v", path, err)
+	}
+
+	conf := DefaultConfig()
+	if file.Default != "" {
+		conf.Default = file.Default
+	}
+	for name, env := range file.Environments {
+		if env == nil {
+			continue
+		}
+		merged := &Environment{Name: name}
+		if def, ok := conf.Environments[name]; ok {
+			*merged = *def
+		}
+		merged.merge(env)
+		conf.Environments[name] = merged
+	}
+	return conf, nil
+}
+
+// merge overrides the fields of e set in o.
+func (e *Environment) merge(o *Environment) {
+	if o.BaseURL != "" {
+		e.BaseURL = o.BaseURL
+	}
+	if o.AppID != "" {
+		e.AppID = o.AppID
+	}
+	if o.AppSecret != "" {
+		e.AppSecret = o.AppSecret
+	}
+	if o.Timeout != 0 {
+		e.Timeout = o.Timeout
+	}
+	if o.Proxy != "" {
+		e.Proxy = o.Proxy
+	}
+	if o.CAFile != "" {
+		e.CAFile = o.CAFile
+	}
+	if o.Pins != nil {
+		e.Pins = o.Pins
+	}
+}
+
+// Environment returns the environment name, or the default one if name is
+// empty.
+func (c *Config) Environment(name string) (*Environment, error) {
+	if name == "" {
+		name = c.Default
+	}
+	env, ok := c.Environments[name]
+	if !ok {
+		names := make([]string, 0, len(c.Environments))
+		for n := range c.Environments {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("icheck: unknown environment %q, want one of %s", name, strings.Join(names, ", "))
+	}
+	e := *env
+	e.Name = name
+	return &e, nil
+}
+
+// Validate checks that e can be used to call the API.
+func (e *Environment) Validate() error {
+	var problems []string
+	u, err := url.Parse(e.BaseURL)
+	switch {
+	case e.BaseURL == "":
+		problems = append(problems, "base URL is empty")
+	case err != nil:
+		problems = append(problems, fmt.Sprintf("base URL: %v", err))
+	case u.Scheme != "http" && u.Scheme != "https" || u.Host == "":
+		problems = append(problems, fmt.Sprintf("base URL %q is not an absolute HTTP URL", e.BaseURL))
+	case u.RawQuery != "" || u.Fragment != "":
+		problems = append(problems, fmt.Sprintf("base URL %q has a query or fragment", e.BaseURL))
+	}
+	if e.AppSecret != "" && e.AppID == "" {
+		problems = append(problems, "app secret is set without app ID")
+	}
+	if e.Timeout < 0 {
+		problems = append(problems, "timeout is negative")
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("icheck: environment %q: %s", e.Name, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Backend returns a backend calling the API of e. Access tokens may only be
+// sent over plain HTTP to the sandbox host or to a loopback address, whatever
+// the name of e.
+func (e *Environment) Backend() (icheck.Backend, error) {
+	httpClient, err := icheck.NewHTTPClient(&icheck.TransportConfig{
+		Timeout: time.Duration(e.Timeout),
+		Proxy:   e.Proxy,
+		CAFile:  e.CAFile,
+		Pins:    e.Pins,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &icheck.BackendConfiguration{
+		URL:        strings.TrimSuffix(e.BaseURL, "/"),
+		HTTPClient: httpClient,
+		// Only the sandbox is served over plain HTTP.
+		AllowInsecureTokens: isSandboxHost(e.BaseURL),
+	}, nil
+}
+
+// isSandboxHost reports whether baseURL points at the host of
+// icheck.SandboxURL.
+func isSandboxHost(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	sandbox, _ := url.Parse(icheck.SandboxURL)
+	return strings.EqualFold(u.Host, sandbox.Host)
+}
+
+// configFiles are the files searched by FromEnv when ICHECK_CONFIG is not
+// set, relative to the working directory and then to the user config
+// directory.
+var configFiles = []string{"icheck.yaml", "icheck.yml", "icheck.toml", "icheck.json"}
+
+// LoadEnvironment returns the environment name, read with the following
+// precedence, highest first:
+//
+//  1. the ICHECK_BASE_URL, ICHECK_APP_ID, ICHECK_APP_SECRET,
+//     ICHECK_TIMEOUT, ICHECK_PROXY and ICHECK_CA_FILE variables;
+//  2. the config file at ICHECK_CONFIG, or the first of icheck.yaml,
+//     icheck.yml, icheck.toml and icheck.json found in the working directory
+//     or in the icheck directory of the user config directory;
+//  3. the built-in sandbox and production environments.
+//
+// An empty name selects ICHECK_ENV, then the default of the config file. The
+// environment is validated.
+func LoadEnvironment(name string) (*Environment, error) {
+	conf, err := loadConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = os.Getenv("ICHECK_ENV")
+	}
+	env, err := conf.Environment(name)
+	if err != nil {
+		return nil, err
+	}
+
+	env.merge(&Environment{
+		BaseURL:   os.Getenv("ICHECK_BASE_URL"),
+		AppID:     os.Getenv("ICHECK_APP_ID"),
+		AppSecret: os.Getenv("ICHECK_APP_SECRET"),
+		Proxy:     os.Getenv("ICHECK_PROXY"),
+		CAFile:    os.Getenv("ICHECK_CA_FILE"),
+	})
+	if v := os.Getenv("ICHECK_TIMEOUT"); v != "" {
+		if err := env.Timeout.UnmarshalText([]byte(v)); err != nil {
+			return nil, fmt.Errorf("icheck: ICHECK_TIMEOUT: %v", err)
+		}
+	}
+
+	if err := env.Validate(); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func loadConfigFromEnv() (*Config, error) {
+	if path := os.Getenv("ICHECK_CONFIG"); path != "" {
+		return LoadConfig(path)
+	}
+	dirs := []string{"."}
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "icheck"))
+	}
+	for _, dir := range dirs {
+		for _, name := range configFiles {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return LoadConfig(path)
+			}
+		}
+	}
+	return DefaultConfig(), nil
+}
+
+// FromEnv returns a client for the environment selected by LoadEnvironment.
+func FromEnv() (*API, error) {
+	env, err := LoadEnvironment("")
+	if err != nil {
+		return nil, err
+	}
+	return NewWithEnvironment(env)
+}
+
+// NewWithEnvironment returns a client calling the API of env, with the app
+// credentials of env.
+func NewWithEnvironment(env *Environment) (*API, error) {
+	backend, err := env.Backend()
+	if err != nil {
+		return nil, err
+	}
+	api := &API{Environment: env, AppID: env.AppID, Secret: env.AppSecret}
+	api.Init(backend)
+	return api, nil
+}
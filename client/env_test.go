@@ -0,0 +1,183 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestLoadEnvironmentPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "icheck.yaml")
+	err := os.WriteFile(path, []byte(`
+default: staging
+environments:
+  staging:
+    base_url: https://staging.example.com
+    timeout: 5s
+  production:
+    proxy: http://proxy.example.com:3128
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ICHECK_CONFIG", path)
+	t.Setenv("ICHECK_ENV", "")
+	t.Setenv("ICHECK_BASE_URL", "")
+	t.Setenv("ICHECK_APP_ID", "")
+	t.Setenv("ICHECK_APP_SECRET", "")
+	t.Setenv("ICHECK_PROXY", "")
+	t.Setenv("ICHECK_TIMEOUT", "")
+
+	env, err := LoadEnvironment("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Name != "staging" || env.BaseURL != "https://staging.example.com" || time.Duration(env.Timeout) != 5*time.Second {
+		t.Errorf("default environment = %+v", env)
+	}
+
+	// Built-in fields survive a partial definition in the file.
+	env, err = LoadEnvironment(Production)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.BaseURL != icheck.ProductionURL || env.Proxy != "http://proxy.example.com:3128" {
+		t.Errorf("production = %+v", env)
+	}
+
+	// Variables win over the file.
+	t.Setenv("ICHECK_ENV", Sandbox)
+	t.Setenv("ICHECK_TIMEOUT", "3s")
+	env, err = LoadEnvironment("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Name != Sandbox || env.BaseURL != icheck.SandboxURL || time.Duration(env.Timeout) != 3*time.Second {
+		t.Errorf("sandbox = %+v", env)
+	}
+
+	t.Setenv("ICHECK_BASE_URL", "sandbox.icheck.com.vn")
+	if _, err := LoadEnvironment(""); err == nil || !strings.Contains(err.Error(), "absolute") {
+		t.Errorf("relative base URL: err = %v", err)
+	}
+	if _, err := LoadEnvironment("qa"); err == nil {
+		t.Error("unknown environment accepted")
+	}
+}
+
+func TestLoadConfigFormats(t *testing.T) {
+	files := map[string]string{
+		"c.toml": "[environments.qa]\nbase_url = \"https://qa.example.com\"\ntimeout = \"2s\"\n",
+		"c.json": `{"environments":{"qa":{"base_url":"https://qa.example.com","timeout":"2s"}}}`,
+	}
+	for name, data := range files {
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		conf, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		env, err := conf.Environment("qa")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if env.BaseURL != "https://qa.example.com" || time.Duration(env.Timeout) != 2*time.Second {
+			t.Errorf("%s: qa = %+v", name, env)
+		}
+	}
+}
+
+func TestEnvironmentTokenTransport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "icheck.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ICHECK_CONFIG", path)
+	t.Setenv("ICHECK_ENV", "")
+	t.Setenv("ICHECK_PROXY", "")
+	t.Setenv("ICHECK_TIMEOUT", "")
+
+	// The exception follows the host requests go to, not the name of the
+	// environment.
+	tests := []struct {
+		env     string
+		baseURL string
+		want    error
+	}{
+		{env: Sandbox},
+		{env: Sandbox, baseURL: "http://127.0.0.1:4336"},
+		{env: Sandbox, baseURL: "http://sandbox.example.com:4336", want: icheck.ErrInsecureToken},
+		{env: Production, baseURL: "http://core.example.com", want: icheck.ErrInsecureToken},
+		{env: Production, baseURL: icheck.SandboxURL},
+	}
+	for _, tt := range tests {
+		t.Setenv("ICHECK_BASE_URL", tt.baseURL)
+		env, err := LoadEnvironment(tt.env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := env.Backend()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = b.(*icheck.BackendConfiguration).NewRequest("GET", "/account", "", nil, &icheck.Params{AccessToken: "tok"})
+		if err != tt.want {
+			t.Errorf("%s at %s: err = %v, want %v", tt.env, env.BaseURL, err, tt.want)
+		}
+	}
+
+	// A sandbox profile edited to point elsewhere is no exception either.
+	if err := os.WriteFile(path, []byte(`{"environments":{"sandbox":{"base_url":"http://evil.example.com"}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ICHECK_BASE_URL", "")
+	env, err := LoadEnvironment(Sandbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := env.Backend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = b.(*icheck.BackendConfiguration).NewRequest("GET", "/account", "", nil, &icheck.Params{AccessToken: "tok"})
+	if err != icheck.ErrInsecureToken {
+		t.Errorf("edited sandbox profile: err = %v, want ErrInsecureToken", err)
+	}
+}
+
+func TestFromEnvAppCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "icheck.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ICHECK_CONFIG", path)
+	t.Setenv("ICHECK_ENV", Production)
+	t.Setenv("ICHECK_BASE_URL", "")
+	t.Setenv("ICHECK_PROXY", "")
+	t.Setenv("ICHECK_TIMEOUT", "")
+	t.Setenv("ICHECK_APP_ID", "env-app")
+	t.Setenv("ICHECK_APP_SECRET", "env-secret")
+
+	api, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.Environment.AppID != "env-app" || api.Environment.AppSecret != "env-secret" {
+		t.Errorf("environment = %+v", api.Environment)
+	}
+	if api.AppID != "env-app" || api.Secret != "env-secret" {
+		t.Errorf("credentials = %q, %q", api.AppID, api.Secret)
+	}
+
+	t.Setenv("ICHECK_APP_ID", "")
+	if _, err := LoadEnvironment(""); err == nil || !strings.Contains(err.Error(), "app ID") {
+		t.Errorf("secret without app ID: err = %v", err)
+	}
+}
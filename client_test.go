@@ -0,0 +1,58 @@
+package icheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDoReconcilesHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        int
+		contentType string
+		body        string
+		status      int
+		unexpected  bool
+	}{
+		{name: "no content", code: http.StatusNoContent},
+		{name: "ok", code: http.StatusOK, contentType: "application/json", body: `{"status":200,"data":{"id":1}}`},
+		{name: "envelope error", code: http.StatusOK, contentType: "application/json", body: `{"status":404,"message":"not found"}`, status: 404},
+		{name: "http error", code: http.StatusBadGateway, contentType: "application/json", body: `{"status":200,"data":{"id":1}}`, status: 502},
+		{name: "empty unauthorized", code: http.StatusUnauthorized, status: 401},
+		{name: "missing status", code: http.StatusServiceUnavailable, contentType: "application/json; charset=utf-8", body: `{"message":"down"}`, status: 503},
+		{name: "html", code: http.StatusBadGateway, contentType: "text/html", body: "<html>Bad Gateway</html>", unexpected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.code)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			b := &BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}
+			err := b.Call("DELETE", "/addresses/1", nil, nil, &AddressResp{})
+			switch e := err.(type) {
+			case nil:
+				if tt.status != 0 || tt.unexpected {
+					t.Errorf("got no error")
+				}
+			case *Error:
+				if e.Status != tt.status || e.Message == "" {
+					t.Errorf("got %+v, want status %d", e, tt.status)
+				}
+			case *ErrUnexpectedResponse:
+				if !tt.unexpected || e.StatusCode != tt.code || e.Body != tt.body {
+					t.Errorf("got %+v", e)
+				}
+			default:
+				t.Errorf("got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// errNoUser is returned when the API answers without the requested user.
+var errNoUser = errors.New("no user in API response")
+
+// parse parses the flags of a command, printing its usage on failure.
+func parse(c *cli, fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(c.stderr)
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	return nil
+}
+
+// usage prints the usage of a command and returns errUsage.
+func usage(c *cli, line string) error {
+	fmt.Fprintf(c.stderr, "usage: icheck %s\n", line)
+	return errUsage
+}
+
+func cmdLogin(c *cli, args []string) error {
+	fs := flag.NewFlagSet("login", flag.ContinueOnError)
+	username := fs.String("u", "", "phone number or email")
+	password := fs.String("p", "", "password, read from stdin if empty")
+	ttl := fs.Int64("ttl", 0, "token lifetime in seconds")
+	if err := parse(c, fs, args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return usage(c, "login -u username [-p password] [-ttl seconds]")
+	}
+	if *password == "" {
+		p, err := c.promptPassword("Password: ")
+		if err != nil {
+			return err
+		}
+		*password = p
+	}
+
+	token, err := c.api.Account.Login(&icheck.LoginParams{Username: *username, Password: *password, TTL: *ttl})
+	if mfa, ok := err.(*icheck.ErrMFARequired); ok {
+		code, perr := c.prompt("Two-factor code: ")
+		if perr != nil {
+			return perr
+		}
+		token, err = c.api.Account.CompleteMFA(mfa.Challenge.ID, code)
+	}
+	if err != nil {
+		return err
+	}
+
+	cred := &credential{AccessToken: token.ID, Username: *username}
+	if token.TTL > 0 {
+		cred.ExpiresAt = time.Now().Add(time.Duration(token.TTL) * time.Second)
+	}
+	if err := c.store.Set(c.key, cred); err != nil {
+		return err
+	}
+	return c.printUsers([]icheck.User{token.User})
+}
+
+func cmdLogout(c *cli, args []string) error {
+	params, err := c.params()
+	if err != nil {
+		return err
+	}
+	if _, err := c.api.Account.Logout(params); err != nil {
+		return err
+	}
+	return c.store.Set(c.key, nil)
+}
+
+func cmdMe(c *cli, args []string) error {
+	params, err := c.params()
+	if err != nil {
+		return err
+	}
+	user, err := c.api.Account.Me(params)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errNoUser
+	}
+	return c.printUsers([]icheck.User{*user})
+}
+
+func cmdUsers(c *cli, args []string) error {
+	if len(args) == 0 {
+		return usage(c, "users get ID | users list -icheck-id ID...")
+	}
+	switch args[0] {
+	case "get":
+		if len(args) != 2 {
+			return usage(c, "users get ID")
+		}
+		params, err := c.params()
+		if err != nil {
+			return err
+		}
+		user, err := c.api.User.Get(args[1], params)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return errNoUser
+		}
+		return c.printUsers([]icheck.User{*user})
+	case "list":
+		var ids stringList
+		fs := flag.NewFlagSet("users list", flag.ContinueOnError)
+		fs.Var(&ids, "icheck-id", "iCheck ID of a user, may be repeated")
+		if err := parse(c, fs, args[1:]); err != nil {
+			return err
+		}
+		users, err := c.api.User.List(&icheck.UserListParams{IcheckID: ids})
+		if err != nil {
+			return err
+		}
+		return c.printUsers(users)
+	}
+	return usage(c, "users get ID | users list -icheck-id ID...")
+}
+
+func (c *cli) printUsers(users []icheck.User) error {
+	return c.out.Print(users, []string{"ID", "ICHECK ID", "NAME", "PHONE", "EMAIL"}, func() [][]string {
+		var rows [][]string
+		for _, u := range users {
+			rows = append(rows, []string{strconv.Itoa(u.ID), u.IcheckID, u.Name, u.Phone, u.Email})
+		}
+		return rows
+	})
+}
+
+func cmdAddresses(c *cli, args []string) error {
+	const line = "addresses list | create [flags] | update [flags] ID | delete ID"
+	if len(args) == 0 {
+		return usage(c, line)
+	}
+	params, err := c.params()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		resp, err := c.api.Address.List(params)
+		if err != nil {
+			return err
+		}
+		return c.printAddresses(resp.Data)
+	case "create", "update":
+		body := &icheck.AddressBody{}
+		fs := flag.NewFlagSet("addresses "+args[0], flag.ContinueOnError)
+		fs.StringVar(&body.Address, "address", "", "street address")
+		fs.Int64Var(&body.City, "city", 0, "city location ID")
+		fs.Int64Var(&body.District, "district", 0, "district location ID")
+		fs.StringVar(&body.Email, "email", "", "contact email")
+		if err := parse(c, fs, args[1:]); err != nil {
+			return err
+		}
+		var resp *icheck.AddressResp
+		if args[0] == "create" {
+			if fs.NArg() != 0 {
+				return usage(c, "addresses create [flags]")
+			}
+			resp, err = c.api.Address.Create(body, params)
+		} else {
+			if fs.NArg() != 1 {
+				return usage(c, "addresses update [flags] ID")
+			}
+			resp, err = c.api.Address.Update(fs.Arg(0), body, params)
+		}
+		if err != nil {
+			return err
+		}
+		return c.printAddresses([]icheck.Address{resp.Data})
+	case "delete":
+		if len(args) != 2 {
+			return usage(c, "addresses delete ID")
+		}
+		_, err := c.api.Address.Delete(args[1], params)
+		return err
+	}
+	return usage(c, line)
+}
+
+func (c *cli) printAddresses(addresses []icheck.Address) error {
+	return c.out.Print(addresses, []string{"ID", "ADDRESS", "CITY", "DISTRICT", "EMAIL"}, func() [][]string {
+		var rows [][]string
+		for _, a := range addresses {
+			rows = append(rows, []string{
+				strconv.FormatUint(a.ID, 10),
+				a.Address,
+				strconv.FormatInt(a.City, 10),
+				strconv.FormatInt(a.District, 10),
+				a.Email,
+			})
+		}
+		return rows
+	})
+}
+
+// locationTypes are the levels of the location tree, from the top.
+var locationTypes = []string{"city", "district", "ward"}
+
+// locationNode is a location and the locations inside it.
+type locationNode struct {
+	Location map[string]interface{} `json:"location"`
+	Children []*locationNode        `json:"children,omitempty"`
+}
+
+func cmdLocations(c *cli, args []string) error {
+	if len(args) == 0 || args[0] != "tree" {
+		return usage(c, "locations tree [-parent ID] [-depth N]")
+	}
+	fs := flag.NewFlagSet("locations tree", flag.ContinueOnError)
+	parent := fs.String("parent", "", "ID of the city to start from")
+	depth := fs.Int("depth", 2, "number of levels to show")
+	if err := parse(c, fs, args[1:]); err != nil {
+		return err
+	}
+
+	level := 0
+	if *parent != "" {
+		level = 1
+	}
+	nodes, err := c.locationTree(*parent, level, *depth)
+	if err != nil {
+		return err
+	}
+	return c.out.Print(nodes, []string{"ID", "NAME"}, func() [][]string {
+		var rows [][]string
+		var walk func([]*locationNode, int)
+		walk = func(nodes []*locationNode, indent int) {
+			for _, n := range nodes {
+				name := strings.Repeat("  ", indent) + str(n.Location["name"])
+				rows = append(rows, []string{str(n.Location["id"]), name})
+				walk(n.Children, indent+1)
+			}
+		}
+		walk(nodes, 0)
+		return rows
+	})
+}
+
+func (c *cli) locationTree(parent string, level, depth int) ([]*locationNode, error) {
+	if depth <= 0 || level >= len(locationTypes) {
+		return nil, nil
+	}
+	q := url.Values{"type": {locationTypes[level]}}
+	if parent != "" {
+		q.Set("parent", parent)
+	}
+	resp, err := c.api.Location.List(q)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []*locationNode
+	for _, loc := range resp.Data {
+		n := &locationNode{Location: loc}
+		n.Children, err = c.locationTree(str(loc["id"]), level+1, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+func cmdSearch(c *cli, args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	typ := fs.String("type", "", "type of results")
+	limit := fs.Int("limit", 0, "maximum number of results")
+	skip := fs.Int("skip", 0, "number of results to skip")
+	if err := parse(c, fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usage(c, "search [-type T] [-limit N] [-skip N] QUERY")
+	}
+
+	q := url.Values{"query": {strings.Join(fs.Args(), " ")}}
+	if *typ != "" {
+		q.Set("type", *typ)
+	}
+	if *limit > 0 {
+		q.Set("limit", strconv.Itoa(*limit))
+	}
+	if *skip > 0 {
+		q.Set("skip", strconv.Itoa(*skip))
+	}
+	resp, err := c.api.Search.Search(q)
+	if err != nil {
+		return err
+	}
+	return c.out.Print(resp.Data, []string{"KEY", "VALUE"}, func() [][]string {
+		var rows [][]string
+		for _, k := range sortedKeys(resp.Data) {
+			rows = append(rows, []string{k, str(resp.Data[k])})
+		}
+		return rows
+	})
+}
+
+// stringList is a flag that may be repeated.
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
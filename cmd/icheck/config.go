@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/icheckteam/icheck-go/client"
+)
+
+// loadEnvironment returns the environment of profile, calling url instead
+// of the environment's API when it is set.
+func loadEnvironment(profile, url string) (*client.Environment, error) {
+	env, err := client.LoadEnvironment(profile)
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		env.BaseURL = url
+		if err := env.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return env, nil
+}
+
+// credential is the access token saved by login.
+type credential struct {
+	AccessToken string    `json:"access_token"`
+	Username    string    `json:"username"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty"`
+}
+
+// credentialKey returns the key of the credential of env in the store. It
+// holds the base URL as well as the profile, so that a token saved for a
+// profile is not sent to another host given with -url.
+func credentialKey(env *client.Environment) string {
+	return env.Name + " " + strings.TrimSuffix(env.BaseURL, "/")
+}
+
+// credentialStore saves a credential per profile and base URL in a JSON file
+// readable by its owner only.
+type credentialStore struct {
+	path string
+}
+
+func newCredentialStore() (*credentialStore, error) {
+	dir := os.Getenv("ICHECK_CONFIG_DIR")
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(base, "icheck")
+	}
+	return &credentialStore{path: filepath.Join(dir, "credentials.json")}, nil
+}
+
+func (s *credentialStore) load() (map[string]*credential, error) {
+	creds := make(map[string]*credential)
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return creds, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("%s: %v", s.path, err)
+	}
+	return creds, nil
+}
+
+// Get returns the unexpired credential saved under key, or nil.
+func (s *credentialStore) Get(key string) (*credential, error) {
+	creds, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	cred := creds[key]
+	if cred == nil || !cred.ExpiresAt.IsZero() && time.Now().After(cred.ExpiresAt) {
+		return nil, nil
+	}
+	return cred, nil
+}
+
+// Set saves cred under key, or removes the credential saved under key if
+// cred is nil.
+func (s *credentialStore) Set(key string, cred *credential) error {
+	creds, err := s.load()
+	if err != nil {
+		return err
+	}
+	if cred == nil {
+		delete(creds, key)
+	} else {
+		creds[key] = cred
+	}
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0600)
+}
@@ -0,0 +1,172 @@
+// Command icheck calls the iCheck API from the command line, e.g. to debug
+// users and addresses on the sandbox.
+//
+// Usage:
+//
+//	icheck [-profile name] [-o table|json] [-url URL] command [args]
+//
+// Commands:
+//
+//	login -u username [-p password]
+//	logout
+//	me
+//	users get ID
+//	users list -icheck-id ID [-icheck-id ID...]
+//	addresses list
+//	addresses create -address A [-city N] [-district N] [-email E]
+//	addresses update [flags] ID
+//	addresses delete ID
+//	locations tree [-parent ID] [-depth N]
+//	search [-type T] [-limit N] [-skip N] QUERY
+//
+// Profiles are the environments of the client package: sandbox, production
+// and those of the config file, selected with -profile or ICHECK_ENV.
+//
+// Access tokens saved by login are kept per profile and API URL in
+// $XDG_CONFIG_HOME/icheck/credentials.json, or in ICHECK_CONFIG_DIR. A token
+// saved for a profile is not used when -url names another API.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	icheck "github.com/icheckteam/icheck-go"
+	"github.com/icheckteam/icheck-go/client"
+	"golang.org/x/term"
+)
+
+// errUsage is returned for invalid command lines, after the usage has been
+// printed.
+var errUsage = errors.New("usage")
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if err == errUsage {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "icheck:", err)
+		os.Exit(1)
+	}
+}
+
+// cli holds the state shared by the commands.
+type cli struct {
+	api     *client.API
+	profile string
+	// key is the key of the credential of the profile and URL in store.
+	key    string
+	store  *credentialStore
+	out    *printer
+	stdin  *bufio.Reader
+	stderr io.Writer
+	// tty is stdin if it is a terminal, to read passwords without echo.
+	tty *os.File
+}
+
+// params returns the params authenticating calls with the saved token.
+func (c *cli) params() (*icheck.Params, error) {
+	cred, err := c.store.Get(c.key)
+	if err != nil {
+		return nil, err
+	}
+	if cred == nil {
+		return nil, fmt.Errorf("not logged in to %s, run icheck login", c.key)
+	}
+	return &icheck.Params{AccessToken: cred.AccessToken}, nil
+}
+
+type command func(c *cli, args []string) error
+
+var commands = map[string]command{
+	"login":     cmdLogin,
+	"logout":    cmdLogout,
+	"me":        cmdMe,
+	"users":     cmdUsers,
+	"addresses": cmdAddresses,
+	"locations": cmdLocations,
+	"search":    cmdSearch,
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("icheck", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	profile := fs.String("profile", "", "environment: sandbox, production or one of the config file (default $ICHECK_ENV)")
+	output := fs.String("o", "table", "output format: table or json")
+	url := fs.String("url", "", "API URL, overriding the one of the profile")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", fs.Arg(0))
+		return errUsage
+	}
+
+	env, err := loadEnvironment(*profile, *url)
+	if err != nil {
+		return err
+	}
+	out, err := newPrinter(*output, stdout)
+	if err != nil {
+		return err
+	}
+	store, err := newCredentialStore()
+	if err != nil {
+		return err
+	}
+
+	api, err := client.NewWithEnvironment(env)
+	if err != nil {
+		return err
+	}
+
+	c := &cli{
+		api:     api,
+		profile: env.Name,
+		key:     credentialKey(env),
+		store:   store,
+		out:     out,
+		stdin:   bufio.NewReader(stdin),
+		stderr:  stderr,
+	}
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		c.tty = f
+	}
+	return cmd(c, fs.Args()[1:])
+}
+
+// prompt writes label to stderr and reads a line from stdin.
+func (c *cli) prompt(label string) (string, error) {
+	fmt.Fprint(c.stderr, label)
+	line, err := c.stdin.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// promptPassword is prompt without echoing the input when stdin is a
+// terminal.
+func (c *cli) promptPassword(label string) (string, error) {
+	if c.tty == nil {
+		return c.prompt(label)
+	}
+	fmt.Fprint(c.stderr, label)
+	password, err := term.ReadPassword(int(c.tty.Fd()))
+	fmt.Fprintln(c.stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoginThenMe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			w.Write([]byte(`{"status":200,"data":{"id":"tok","ttl":3600,"user":{"id":1,"social_name":"An"}}}`))
+		case "/account":
+			if r.Header.Get("access-token") != "tok" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"status":401}`))
+				return
+			}
+			w.Write([]byte(`{"status":200,"data":{"id":1,"icheck_id":"i-1","social_name":"An","phone":"0977465849"}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("ICHECK_CONFIG_DIR", t.TempDir())
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-url", srv.URL, "login", "-u", "0977465849"}, strings.NewReader("secret123\n"), &stdout, &stderr); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	stdout.Reset()
+	if err := run([]string{"-url", srv.URL, "me"}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("me: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "i-1") || !strings.Contains(stdout.String(), "0977465849") {
+		t.Errorf("me printed:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"-url", srv.URL, "-o", "json", "me"}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("me -o json: %v", err)
+	}
+	if !strings.Contains(stdout.String(), `"icheck_id": "i-1"`) {
+		t.Errorf("me -o json printed:\n%s", stdout.String())
+	}
+}
+
+func TestMissingData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":200}`))
+	}))
+	defer srv.Close()
+	t.Setenv("ICHECK_CONFIG_DIR", t.TempDir())
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-url", srv.URL, "login", "-u", "0977465849", "-p", "secret123"}, nil, &stdout, &stderr); err == nil {
+		t.Fatal("login without token succeeded")
+	}
+
+	store, _ := newCredentialStore()
+	if err := store.Set("sandbox "+srv.URL, &credential{AccessToken: "tok"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"me"}, {"users", "get", "1"}} {
+		if err := run(append([]string{"-url", srv.URL}, args...), nil, &stdout, &stderr); err != errNoUser {
+			t.Errorf("%v: err = %v, want errNoUser", args, err)
+		}
+	}
+}
+
+func TestSavedTokenStaysWithItsURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("access-token") != "" {
+			t.Errorf("token sent to %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"status":200,"data":{"id":1}}`))
+	}))
+	defer srv.Close()
+	t.Setenv("ICHECK_CONFIG_DIR", t.TempDir())
+	t.Setenv("ICHECK_ENV", "sandbox")
+
+	store, _ := newCredentialStore()
+	env, err := loadEnvironment("sandbox", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(credentialKey(env), &credential{AccessToken: "sandbox-tok"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = run([]string{"-url", srv.URL, "me"}, nil, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "not logged in") {
+		t.Errorf("me with -url: err = %v, want not logged in", err)
+	}
+}
@@ -0,0 +1,137 @@
+// Package coalesce provides an icheck.Backend that merges identical GET
+// calls made at the same time into a single HTTP request.
+package coalesce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// call is a request in flight shared by one or more callers.
+type call struct {
+	done chan struct{}
+	body json.RawMessage
+	info icheck.CallInfo
+	err  error
+}
+
+// callKey identifies the calls that can share a request. Its fields are kept
+// apart so that no two different calls have the same key.
+type callKey struct {
+	token   string
+	path    string
+	query   string
+	headers string
+}
+
+// Backend wraps an icheck.Backend. Concurrent GET calls with the same path,
+// form, access token and headers share one request; every caller decodes its own
+// copy of the response, so callers never share structs.
+//
+// The shared request is not canceled when one of its callers gives up. A
+// caller whose context is done returns the context's error right away while
+// the others keep waiting.
+type Backend struct {
+	B icheck.Backend
+
+	mu    sync.Mutex
+	calls map[callKey]*call
+}
+
+// NewBackend returns a Backend that coalesces calls made through b.
+func NewBackend(b icheck.Backend) *Backend {
+	return &Backend{B: b, calls: make(map[callKey]*call)}
+}
+
+// Call is the Backend.Call implementation.
+func (b *Backend) Call(method, path string, form *icheck.RequestValues, params *icheck.Params, v interface{}) error {
+	if strings.ToUpper(method) != "GET" || params != nil && params.Body != nil {
+		return b.B.Call(method, path, form, params, v)
+	}
+
+	p := icheck.Params{}
+	if params != nil {
+		p = *params
+	}
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	key := callKey{token: p.AccessToken, path: path, headers: headerKey(p.Headers)}
+	if form != nil && !form.Empty() {
+		key.query = form.Encode()
+	}
+
+	b.mu.Lock()
+	c, ok := b.calls[key]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		b.calls[key] = c
+		go b.do(key, c, method, path, form, p)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.done:
+	}
+
+	if info := icheck.CallInfoFromContext(ctx); info != nil {
+		*info = c.info
+	}
+	if c.err != nil {
+		return c.err
+	}
+	return json.Unmarshal(c.body, v)
+}
+
+// headerKey returns a canonical form of h, so that only calls sending the
+// same headers, such as the same If-None-Match, are merged. Names and values
+// are length-prefixed so that they cannot run into each other.
+func headerKey(h http.Header) string {
+	if len(h) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	for _, name := range names {
+		for _, v := range h[name] {
+			for _, s := range []string{http.CanonicalHeaderKey(name), v} {
+				sb.WriteString(strconv.Itoa(len(s)))
+				sb.WriteString(":")
+				sb.WriteString(s)
+			}
+		}
+	}
+	return sb.String()
+}
+
+func (b *Backend) do(key callKey, c *call, method, path string, form *icheck.RequestValues, p icheck.Params) {
+	defer func() {
+		b.mu.Lock()
+		delete(b.calls, key)
+		b.mu.Unlock()
+		close(c.done)
+	}()
+
+	ctx := context.Background()
+	if p.Context != nil {
+		ctx = context.WithoutCancel(p.Context)
+	}
+	p.Context = icheck.WithCallInfo(ctx, &c.info)
+
+	c.err = b.B.Call(method, path, form, &p, &c.body)
+}
@@ -0,0 +1,134 @@
+package coalesce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// waitingContext counts the callers waiting for a call: Backend.Call asks
+// for Done once it has joined the call in flight.
+type waitingContext struct {
+	context.Context
+	waiting *int32
+}
+
+func (c waitingContext) Done() <-chan struct{} {
+	atomic.AddInt32(c.waiting, 1)
+	return c.Context.Done()
+}
+
+func waitFor(waiting *int32, n int32) {
+	for atomic.LoadInt32(waiting) < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBackendCoalescesIdenticalGets(t *testing.T) {
+	release := make(chan struct{})
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		<-release
+		w.Write([]byte(`{"status":200,"data":{"id":1,"social_name":"Lan"}}`))
+	}))
+	defer srv.Close()
+
+	b := NewBackend(&icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()})
+
+	const n = 5
+	var waiting int32
+	ctx := waitingContext{context.Background(), &waiting}
+	users := make([]*icheck.User, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp := &icheck.UserResponse{}
+			errs[i] = b.Call("GET", "/account", nil, &icheck.Params{AccessToken: "t", Context: ctx}, resp)
+			users[i] = resp.User
+		}(i)
+	}
+	waitFor(&waiting, n)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Fatalf("got %d requests, want 1", requests)
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if users[i].Name != "Lan" {
+			t.Fatalf("user %d = %+v", i, users[i])
+		}
+	}
+	users[0].Name = "changed"
+	if users[1].Name != "Lan" {
+		t.Error("callers share the decoded user")
+	}
+}
+
+func TestBackendKeepsCallsWithDifferentHeadersApart(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		<-release
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"status":200,"data":{"id":1}}`))
+	}))
+	defer srv.Close()
+
+	b := NewBackend(&icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()})
+
+	var waiting int32
+	ctx := waitingContext{context.Background(), &waiting}
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i, h := range []http.Header{{"If-None-Match": {`"v1"`}}, nil} {
+		wg.Add(1)
+		go func(i int, h http.Header) {
+			defer wg.Done()
+			errs[i] = b.Call("GET", "/account", nil, &icheck.Params{AccessToken: "t", Headers: h, Context: ctx}, &icheck.UserResponse{})
+		}(i, h)
+	}
+	waitFor(&waiting, 2)
+	close(release)
+	wg.Wait()
+
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2", requests)
+	}
+	if errs[0] != icheck.ErrNotModified || errs[1] != nil {
+		t.Errorf("errs = %v", errs)
+	}
+}
+
+func TestCallKeysDoNotCollide(t *testing.T) {
+	pairs := [][2]callKey{
+		{{token: "a b", path: "/x"}, {token: "a", path: "b /x"}},
+		{{path: "/x", headers: headerKey(http.Header{"A": {"1"}})}, {path: "/x" + headerKey(http.Header{"A": {"1"}})}},
+		{{headers: headerKey(http.Header{"A": {"1:1B"}})}, {headers: headerKey(http.Header{"A": {"1"}, "B": {""}})}},
+	}
+	for _, p := range pairs {
+		if p[0] == p[1] {
+			t.Errorf("%+v and %+v collide", p[0], p[1])
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package icheck
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// DefaultMaxBodySize is the largest response body read by a
+// BackendConfiguration whose MaxBodySize is zero.
+const DefaultMaxBodySize = 10 << 20
+
+// ErrResponseTooLarge is returned when a response body exceeds the maximum
+// body size of the backend.
+var ErrResponseTooLarge = errors.New("icheck: response body too large")
+
+// responseBody returns the decompressed body of res, failing with
+// ErrResponseTooLarge once more than max bytes have been read.
+func responseBody(res *http.Response, max int64) (io.Reader, error) {
+	var body io.Reader = res.Body
+	switch strings.ToLower(strings.TrimSpace(res.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(body)
+		if err == io.EOF {
+			return strings.NewReader(""), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		body = zr
+	case "deflate":
+		// "deflate" should be zlib wrapped, but some servers send raw
+		// deflate data.
+		br := bufio.NewReader(body)
+		head, err := br.Peek(2)
+		if len(head) == 0 && err == io.EOF {
+			return strings.NewReader(""), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if head[0]&0x0f == 8 && (uint16(head[0])<<8|uint16(head[1]))%31 == 0 {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, err
+			}
+			body = zr
+		} else {
+			body = flate.NewReader(br)
+		}
+	}
+	if max <= 0 {
+		max = DefaultMaxBodySize
+	}
+	return &limitReader{r: body, n: max}, nil
+}
+
+// limitReader is like io.LimitedReader but fails instead of stopping at the
+// limit, so a truncated body is never mistaken for a complete one.
+type limitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// decodeResponse decodes the response envelope read from r in a single pass.
+// Once the status is known, the members of the envelope are decoded straight
+// into the matching fields of v; members preceding the status are held until
+// then. The status, members with no matching field, and every member of an
+// error response, are returned in rest so an error can be built from them.
+//
+// Targets that are not structs, such as maps or json.RawMessage, cannot be
+// filled member by member; the envelope is then buffered and decoded twice.
+func decodeResponse(r io.Reader, v interface{}) (status int, rest map[string]json.RawMessage, err error) {
+	dec := json.NewDecoder(r)
+
+	fields, ok := envelopeFields(v)
+	if !ok {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return 0, nil, err
+		}
+		if err := json.Unmarshal(raw, &rest); err != nil {
+			return 0, nil, err
+		}
+		if s, ok := rest["status"]; ok {
+			if err := json.Unmarshal(s, &status); err != nil {
+				return 0, nil, err
+			}
+		}
+		if status < 400 {
+			return status, rest, json.Unmarshal(raw, v)
+		}
+		return status, rest, nil
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return 0, nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return 0, nil, fmt.Errorf("icheck: response is not a JSON object")
+	}
+
+	// Members preceding the status are held until it is known, so nothing
+	// is decoded into v from an error response.
+	type member struct {
+		key string
+		raw json.RawMessage
+	}
+	var pending []member
+	seenStatus := false
+	flush := func() error {
+		for _, m := range pending {
+			field, ok := fields[strings.ToLower(m.key)]
+			if !ok || status >= 400 {
+				rest[m.key] = m.raw
+				continue
+			}
+			if err := json.Unmarshal(m.raw, field.Addr().Interface()); err != nil {
+				return err
+			}
+		}
+		pending = nil
+		return nil
+	}
+
+	rest = make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, nil, err
+		}
+		key := tok.(string)
+		name := strings.ToLower(key)
+
+		if name == "status" {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return 0, nil, err
+			}
+			if err := json.Unmarshal(raw, &status); err != nil {
+				return 0, nil, err
+			}
+			rest[key] = raw
+			if field, ok := fields[name]; ok {
+				if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+					return 0, nil, err
+				}
+			}
+			seenStatus = true
+			if err := flush(); err != nil {
+				return 0, nil, err
+			}
+			continue
+		}
+
+		field, ok := fields[name]
+		if !ok || !seenStatus || status >= 400 {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return 0, nil, err
+			}
+			if ok && !seenStatus {
+				pending = append(pending, member{key, raw})
+			} else {
+				rest[key] = raw
+			}
+			continue
+		}
+		if err := dec.Decode(field.Addr().Interface()); err != nil {
+			return 0, nil, err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return 0, nil, err
+	}
+	// Envelopes without a status are decoded as successful; the caller
+	// falls back to the HTTP status.
+	if err := flush(); err != nil {
+		return 0, nil, err
+	}
+
+	return status, rest, nil
+}
+
+// envelopeFields maps the lower cased JSON names of the fields of the struct
+// pointed to by v to the fields themselves. It reports false when v does not
+// point to a struct.
+func envelopeFields(v interface{}) (map[string]reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, false
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return nil, false
+	}
+	if _, ok := v.(json.Unmarshaler); ok {
+		return nil, false
+	}
+
+	fields := make(map[string]reflect.Value)
+	for _, f := range reflect.VisibleFields(rv.Type()) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			tag = strings.Split(tag, ",")[0]
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		fv, err := rv.FieldByIndexErr(f.Index)
+		if err != nil {
+			continue
+		}
+		name = strings.ToLower(name)
+		if _, dup := fields[name]; !dup {
+			fields[name] = fv
+		}
+	}
+	return fields, true
+}
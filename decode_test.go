@@ -0,0 +1,86 @@
+package icheck
+
+import (
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDoGzipResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Accept-Encoding = %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(`{"data":[{"id":1}],"status":200}`))
+		zw.Close()
+	}))
+	defer srv.Close()
+
+	b := &BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}
+	resp := &LocationsResponse{}
+	if err := b.Call("GET", "/locations", nil, nil, resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != 200 || len(resp.Data) != 1 {
+		t.Errorf("got %+v", resp)
+	}
+}
+
+func TestDoErrorStatusAndSizeLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/register":
+			w.Write([]byte(`{"status":400,"error":"E_VALIDATION","invalidAttributes":{"username":[{"rule":"required","message":"username is required"}]}}`))
+		default:
+			w.Write([]byte(`{"status":200,"data":"` + strings.Repeat("x", 300) + `"}`))
+		}
+	}))
+	defer srv.Close()
+
+	b := &BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client(), MaxBodySize: 200}
+	err := b.Call("POST", "/register", nil, nil, &UserResponse{})
+	if badRequest, ok := err.(*ErrBadRequest); !ok || badRequest.Error() != "username is required" {
+		t.Errorf("got %v", err)
+	}
+
+	err = b.Call("GET", "/search", nil, nil, &SearchResponse{})
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("got %v, want ErrResponseTooLarge", err)
+	}
+}
+
+func TestDecodeResponseStatusAfterData(t *testing.T) {
+	tests := []struct {
+		body    string
+		status  int
+		address string
+		message string
+	}{
+		{body: `{"data":{"address":"leaked"},"status":404,"message":"not found"}`, status: 404, message: "not found"},
+		{body: `{"message":"ok","data":{"address":"home"},"status":200}`, status: 200, address: "home"},
+		{body: `{"data":{"address":"home"}}`, address: "home"},
+	}
+	for _, tt := range tests {
+		resp := &AddressResp{}
+		status, rest, err := decodeResponse(strings.NewReader(tt.body), resp)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.body, err)
+		}
+		if status != tt.status || resp.Data.Address != tt.address {
+			t.Errorf("%s: status = %d, address = %q", tt.body, status, resp.Data.Address)
+		}
+		if tt.status >= 400 {
+			if _, ok := rest["data"]; !ok {
+				t.Errorf("%s: data missing from rest", tt.body)
+			}
+			if string(rest["message"]) != `"`+tt.message+`"` {
+				t.Errorf("%s: message = %s", tt.body, rest["message"])
+			}
+		}
+	}
+}
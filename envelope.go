@@ -0,0 +1,60 @@
+package icheck
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Envelope is the wrapper the iCheck API puts around every response body,
+// along with details about the HTTP exchange that produced it.
+type Envelope[T any] struct {
+	Status int `json:"status"`
+	Data   T   `json:"data"`
+	// Total, Limit and Skip are set by paginated endpoints.
+	Total int `json:"total,omitempty"`
+	Limit int `json:"limit,omitempty"`
+	Skip  int `json:"skip,omitempty"`
+
+	Meta ResponseMeta `json:"-"`
+}
+
+// ResponseMeta describes the HTTP exchange behind an Envelope. It is empty
+// when the response was served without calling the API, e.g. from a cache.
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	// RequestID is the X-Request-Id header set by the API, useful when
+	// reporting issues to iCheck.
+	RequestID string
+	Latency   time.Duration
+	Attempts  int
+}
+
+// CallEnvelope calls the API through b and returns the whole response
+// envelope, with Data decoded as T.
+func CallEnvelope[T any](b Backend, method, path string, form *RequestValues, params *Params) (*Envelope[T], error) {
+	p := Params{}
+	if params != nil {
+		p = *params
+	}
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	info := &CallInfo{}
+	p.Context = WithCallInfo(ctx, info)
+
+	env := &Envelope[T]{}
+	if err := b.Call(method, path, form, &p, env); err != nil {
+		return nil, err
+	}
+	env.Meta = ResponseMeta{
+		StatusCode: info.StatusCode,
+		Header:     info.Header,
+		RequestID:  info.Header.Get("X-Request-Id"),
+		Latency:    info.Latency,
+		Attempts:   info.Attempts,
+	}
+	return env, nil
+}
@@ -0,0 +1,27 @@
+package icheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCallEnvelope(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Write([]byte(`{"status":200,"total":42,"limit":1,"data":[{"id":7,"social_name":"Lan"}]}`))
+	}))
+	defer srv.Close()
+
+	b := &BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}
+	env, err := CallEnvelope[[]User](b, "GET", "/users", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Status != 200 || env.Total != 42 || len(env.Data) != 1 || env.Data[0].Name != "Lan" {
+		t.Errorf("got %+v", env)
+	}
+	if env.Meta.RequestID != "req-1" || env.Meta.StatusCode != 200 || env.Meta.Attempts != 1 {
+		t.Errorf("meta = %+v", env.Meta)
+	}
+}
@@ -0,0 +1,36 @@
+package icheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventType is the type of a webhook Event.
+type EventType string
+
+const (
+	// EventAccountDeleted is sent when a scheduled account deletion is
+	// completed. Its data is an AccountDeletion.
+	EventAccountDeleted EventType = "account.deleted"
+)
+
+// Event is a notification sent by the API to webhook endpoints.
+type Event struct {
+	ID      string          `json:"id"`
+	Type    EventType       `json:"type"`
+	Created time.Time       `json:"created"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// AccountDeletion returns the data of an EventAccountDeleted event.
+func (e *Event) AccountDeletion() (*AccountDeletion, error) {
+	if e.Type != EventAccountDeleted {
+		return nil, fmt.Errorf("icheck: event %s is not %s", e.Type, EventAccountDeleted)
+	}
+	d := &AccountDeletion{}
+	if err := json.Unmarshal(e.Data, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
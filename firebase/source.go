@@ -0,0 +1,96 @@
+package firebase
+
+import (
+	"sync"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// defaultRefreshBefore is how long before expiry a TokenSource requests a
+// new token, leaving time to exchange it with Firebase.
+const defaultRefreshBefore = 5 * time.Minute
+
+// Minter requests new Firebase custom tokens from the API. It is implemented
+// by *account.Client.
+type Minter interface {
+	FirebaseToken(params *icheck.Params) (*icheck.AccessToken, error)
+}
+
+// TokenSource returns a valid Firebase custom token for a user, requesting
+// a new one through the API when the current one is about to expire. It is
+// safe for concurrent use.
+type TokenSource struct {
+	Minter Minter
+	// ProjectID is the Firebase project the tokens are issued for.
+	ProjectID string
+	Keys      Keys
+	// Params identifies the user to the API.
+	Params *icheck.Params
+	// IcheckID, when set, is checked against the uid of every token.
+	IcheckID string
+	// RefreshBefore is how long before expiry a new token is requested.
+	// Defaults to 5 minutes.
+	RefreshBefore time.Duration
+
+	mu     sync.Mutex
+	token  string
+	claims *Claims
+}
+
+// NewTokenSource returns a TokenSource starting from the Firebase token of
+// at, which may be empty.
+func NewTokenSource(m Minter, projectID string, keys Keys, at *icheck.AccessToken, params *icheck.Params) *TokenSource {
+	s := &TokenSource{Minter: m, ProjectID: projectID, Keys: keys, Params: params}
+	if at != nil {
+		s.token = at.FirebaseToken
+		s.IcheckID = at.User.IcheckID
+	}
+	return s
+}
+
+// Token returns the current token and its claims, refreshing it if needed.
+func (s *TokenSource) Token() (string, *Claims, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.claims == nil && s.token != "" {
+		claims, err := s.verify(s.token)
+		if err != nil && err != ErrTokenExpired {
+			return "", nil, err
+		}
+		s.claims = claims
+	}
+	if s.claims != nil && time.Until(expiry(s.claims)) > s.refreshBefore() {
+		return s.token, s.claims, nil
+	}
+
+	at, err := s.Minter.FirebaseToken(s.Params)
+	if err != nil {
+		return "", nil, err
+	}
+	claims, err := s.verify(at.FirebaseToken)
+	if err != nil {
+		return "", nil, err
+	}
+	s.token, s.claims = at.FirebaseToken, claims
+	return s.token, s.claims, nil
+}
+
+func (s *TokenSource) verify(token string) (*Claims, error) {
+	claims, err := Verify(token, s.ProjectID, s.Keys)
+	if err != nil {
+		return nil, err
+	}
+	if s.IcheckID != "" && claims.UID != s.IcheckID {
+		return nil, ErrUserMismatch
+	}
+	return claims, nil
+}
+
+func (s *TokenSource) refreshBefore() time.Duration {
+	if s.RefreshBefore > 0 {
+		return s.RefreshBefore
+	}
+	return defaultRefreshBefore
+}
@@ -0,0 +1,175 @@
+// Package firebase verifies the Firebase custom tokens returned in
+// icheck.AccessToken and keeps them fresh.
+package firebase
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// Audience is the audience of every Firebase custom token.
+const Audience = "https://identitytoolkit.googleapis.com/google.identity.identitytoolkit.v1.IdentityToolkit"
+
+// Issuer returns the issuer of the tokens of the Firebase project projectID.
+func Issuer(projectID string) string {
+	return "https://securetoken.google.com/" + projectID
+}
+
+var (
+	// ErrTokenExpired is returned for tokens past their expiry time.
+	ErrTokenExpired = errors.New("icheck: firebase token expired")
+	// ErrUnknownKey is returned when a token is signed by none of the keys.
+	ErrUnknownKey = errors.New("icheck: firebase token signed by unknown key")
+	// ErrUserMismatch is returned when a token was minted for another user.
+	ErrUserMismatch = errors.New("icheck: firebase token belongs to another user")
+	// ErrIssuerMismatch is returned when a token was issued for another
+	// Firebase project.
+	ErrIssuerMismatch = errors.New("icheck: firebase token issued for another project")
+	// ErrNoSubject is returned for tokens without a sub claim.
+	ErrNoSubject = errors.New("icheck: firebase token has no subject")
+)
+
+// Claims are the claims of a Firebase custom token. The UID of tokens minted
+// by iCheck is the IcheckID of the user.
+type Claims struct {
+	jwt.RegisteredClaims
+	UID string `json:"uid"`
+	// Claims holds the developer claims copied into the Firebase ID token.
+	Claims map[string]interface{} `json:"claims,omitempty"`
+}
+
+// IcheckID returns the iCheck ID of the user the token was minted for.
+func (c *Claims) IcheckID() string {
+	return c.UID
+}
+
+// Keys holds the public keys of the service account signing the tokens,
+// indexed by key ID.
+type Keys map[string]*rsa.PublicKey
+
+// ParseCertificates parses the PEM encoded X.509 certificates published by
+// Google for a service account, at
+// https://www.googleapis.com/robot/v1/metadata/x509/{service account email}.
+func ParseCertificates(certs map[string]string) (Keys, error) {
+	keys := make(Keys, len(certs))
+	for kid, data := range certs {
+		block, _ := pem.Decode([]byte(data))
+		if block == nil {
+			return nil, fmt.Errorf("icheck: key %q is not PEM encoded", kid)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("icheck: key %q: %v", kid, err)
+		}
+		key, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("icheck: key %q is not an RSA key", kid)
+		}
+		keys[kid] = key
+	}
+	return keys, nil
+}
+
+// Decode returns the claims of token without verifying its signature. Use it
+// only to inspect tokens; use Verify before trusting them.
+func Decode(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Verify checks that token is a Firebase custom token of the project
+// projectID, signed with one of keys, with a subject and not expired, and
+// returns its claims. Tokens without a kid header, as minted by the Firebase
+// Admin SDKs, are checked against every key.
+func Verify(token, projectID string, keys Keys) (*Claims, error) {
+	kid, err := keyID(token)
+	if err != nil {
+		return nil, err
+	}
+	if kid != "" {
+		key, ok := keys[kid]
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		return verifyWith(token, projectID, key)
+	}
+	for _, key := range keys {
+		claims, err := verifyWith(token, projectID, key)
+		if errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+			continue
+		}
+		return claims, err
+	}
+	return nil, ErrUnknownKey
+}
+
+// keyID returns the kid header of token.
+func keyID(token string) (string, error) {
+	t, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	if err != nil {
+		return "", err
+	}
+	kid, _ := t.Header["kid"].(string)
+	return kid, nil
+}
+
+// verifyWith checks token against key.
+func verifyWith(token, projectID string, key *rsa.PublicKey) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return key, nil
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithAudience(Audience),
+		jwt.WithIssuer(Issuer(projectID)),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return nil, ErrIssuerMismatch
+	case err != nil:
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, ErrNoSubject
+	}
+	if claims.UID == "" {
+		return nil, errors.New("icheck: firebase token has no uid")
+	}
+	return claims, nil
+}
+
+// VerifyUser is Verify, also checking that token was minted for user.
+func VerifyUser(token, projectID string, keys Keys, user *icheck.User) (*Claims, error) {
+	claims, err := Verify(token, projectID, keys)
+	if err != nil {
+		return nil, err
+	}
+	if claims.UID != user.IcheckID {
+		return nil, ErrUserMismatch
+	}
+	return claims, nil
+}
+
+// expiry returns the expiry time of claims, or the zero time if it has none.
+func expiry(claims *Claims) time.Time {
+	if claims.ExpiresAt == nil {
+		return time.Time{}
+	}
+	return claims.ExpiresAt.Time
+}
@@ -0,0 +1,148 @@
+package firebase
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func testKeys(t *testing.T) (*rsa.PrivateKey, Keys) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keys, err := ParseCertificates(map[string]string{"k1": string(cert)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, keys
+}
+
+const testProject = "icheck-test"
+
+func sign(t *testing.T, priv *rsa.PrivateKey, kid, uid string, exp time.Time) string {
+	return signClaims(t, priv, kid, testClaims(uid, exp))
+}
+
+func testClaims(uid string, exp time.Time) *Claims {
+	return &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer(testProject),
+			Subject:   uid,
+			Audience:  jwt.ClaimStrings{Audience},
+			IssuedAt:  jwt.NewNumericDate(exp.Add(-time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+		UID: uid,
+	}
+}
+
+func signClaims(t *testing.T, priv *rsa.PrivateKey, kid string, claims *Claims) string {
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
+	s, err := tok.SignedString(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestVerify(t *testing.T) {
+	priv, keys := testKeys(t)
+	now := time.Now()
+
+	claims, err := VerifyUser(sign(t, priv, "k1", "i-1", now.Add(time.Hour)), testProject, keys, &icheck.User{IcheckID: "i-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.IcheckID() != "i-1" {
+		t.Errorf("IcheckID = %q", claims.IcheckID())
+	}
+
+	if _, err := Verify(sign(t, priv, "", "i-1", now.Add(time.Hour)), testProject, keys); err != nil {
+		t.Errorf("no kid: err = %v", err)
+	}
+	other, _ := testKeys(t)
+	if _, err := Verify(sign(t, other, "", "i-1", now.Add(time.Hour)), testProject, keys); err != ErrUnknownKey {
+		t.Errorf("no kid, other key: err = %v", err)
+	}
+	if _, err := Verify(sign(t, priv, "k1", "i-1", now.Add(-time.Minute)), testProject, keys); err != ErrTokenExpired {
+		t.Errorf("expired: err = %v", err)
+	}
+	if _, err := Verify(sign(t, priv, "k2", "i-1", now.Add(time.Hour)), testProject, keys); err != ErrUnknownKey {
+		t.Errorf("unknown key: err = %v", err)
+	}
+	if _, err := VerifyUser(sign(t, priv, "k1", "i-2", now.Add(time.Hour)), testProject, keys, &icheck.User{IcheckID: "i-1"}); err != ErrUserMismatch {
+		t.Errorf("other user: err = %v", err)
+	}
+
+	claims = testClaims("i-1", now.Add(time.Hour))
+	claims.Issuer = Issuer("other-project")
+	if _, err := Verify(signClaims(t, priv, "k1", claims), testProject, keys); err != ErrIssuerMismatch {
+		t.Errorf("other project: err = %v", err)
+	}
+	if _, err := Verify(sign(t, priv, "k1", "i-1", now.Add(time.Hour)), "other-project", keys); err != ErrIssuerMismatch {
+		t.Errorf("other expected project: err = %v", err)
+	}
+	claims = testClaims("i-1", now.Add(time.Hour))
+	claims.Subject = ""
+	if _, err := Verify(signClaims(t, priv, "k1", claims), testProject, keys); err != ErrNoSubject {
+		t.Errorf("no subject: err = %v", err)
+	}
+}
+
+type minterFunc func(*icheck.Params) (*icheck.AccessToken, error)
+
+func (f minterFunc) FirebaseToken(params *icheck.Params) (*icheck.AccessToken, error) {
+	return f(params)
+}
+
+func TestTokenSourceRefreshesExpiredToken(t *testing.T) {
+	priv, keys := testKeys(t)
+	fresh := sign(t, priv, "k1", "i-1", time.Now().Add(time.Hour))
+	calls := 0
+	m := minterFunc(func(*icheck.Params) (*icheck.AccessToken, error) {
+		calls++
+		return &icheck.AccessToken{FirebaseToken: fresh}, nil
+	})
+
+	at := &icheck.AccessToken{
+		FirebaseToken: sign(t, priv, "k1", "i-1", time.Now().Add(time.Minute)),
+		User:          icheck.User{IcheckID: "i-1"},
+	}
+	s := NewTokenSource(m, testProject, keys, at, nil)
+	for i := 0; i < 2; i++ {
+		tok, _, err := s.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok != fresh {
+			t.Errorf("token %d was not refreshed", i)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("minter called %d times, want 1", calls)
+	}
+}
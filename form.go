@@ -0,0 +1,393 @@
+package icheck
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormMarshaler is implemented by types that know how to add themselves to a
+// form under the given key.
+type FormMarshaler interface {
+	MarshalForm(key string, form *RequestValues) error
+}
+
+// FormUnmarshaler is implemented by types that know how to read themselves
+// from a form under the given key. It is the counterpart of FormMarshaler.
+type FormUnmarshaler interface {
+	UnmarshalForm(key string, form *RequestValues) error
+}
+
+var (
+	formMarshalerType   = reflect.TypeOf((*FormMarshaler)(nil)).Elem()
+	formUnmarshalerType = reflect.TypeOf((*FormUnmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// EncodeForm turns a struct into RequestValues using the "form" tags of its
+// fields:
+//
+//	type AddressBody struct {
+//		Address string `form:"address,omitempty"`
+//		City    int64  `form:"city,omitempty"`
+//	}
+//
+// Untagged fields are ignored, except embedded structs whose fields are
+// promoted. With omitempty, zero values are left out. Slices repeat their
+// key, maps and structs nest their entries in brackets ("meta[color]=red"),
+// and times are encoded as Unix timestamps. Types implementing FormMarshaler
+// encode themselves.
+func EncodeForm(v interface{}) (*RequestValues, error) {
+	form := &RequestValues{}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return form, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("icheck: cannot encode %s as a form", rv.Type())
+	}
+	if err := encodeStruct(form, "", rv); err != nil {
+		return nil, err
+	}
+	return form, nil
+}
+
+// formTag is a parsed "form" struct tag.
+type formTag struct {
+	name      string
+	omitempty bool
+}
+
+func parseFormTag(f reflect.StructField) (formTag, bool) {
+	tag, ok := f.Tag.Lookup("form")
+	if !ok || tag == "-" {
+		return formTag{}, false
+	}
+	parts := strings.Split(tag, ",")
+	t := formTag{name: parts[0]}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			t.omitempty = true
+		}
+	}
+	if t.name == "" {
+		t.name = f.Name
+	}
+	return t, true
+}
+
+func encodeStruct(form *RequestValues, prefix string, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag, ok := parseFormTag(f)
+		if !ok {
+			if f.Anonymous && f.IsExported() && f.Tag.Get("form") != "-" && indirectType(f.Type).Kind() == reflect.Struct {
+				fv := rv.Field(i)
+				if fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						continue
+					}
+					fv = fv.Elem()
+				}
+				if err := encodeStruct(form, prefix, fv); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if tag.omitempty && fv.IsZero() {
+			continue
+		}
+		if err := encodeValue(form, nestKey(prefix, tag.name), fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeValue(form *RequestValues, key string, rv reflect.Value) error {
+	if rv.Type().Implements(formMarshalerType) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil
+		}
+		return rv.Interface().(FormMarshaler).MarshalForm(key, form)
+	}
+	if rv.CanAddr() && rv.Addr().Type().Implements(formMarshalerType) {
+		return rv.Addr().Interface().(FormMarshaler).MarshalForm(key, form)
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return encodeValue(form, key, rv.Elem())
+	case reflect.String:
+		form.Add(key, rv.String())
+	case reflect.Bool:
+		form.Add(key, strconv.FormatBool(rv.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		form.Add(key, strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		form.Add(key, strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		form.Add(key, strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits()))
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			elem := rv.Index(i)
+			elemKey := key
+			if et := indirectType(elem.Type()); et.Kind() == reflect.Map || et.Kind() == reflect.Struct && et != timeType {
+				elemKey = fmt.Sprintf("%s[%d]", key, i)
+			}
+			if err := encodeValue(form, elemKey, elem); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := rv.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k.Interface())
+		}
+		sort.Sort(byName{names, keys})
+		for i, k := range keys {
+			if err := encodeValue(form, nestKey(key, names[i]), rv.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if rv.Type() == timeType {
+			form.Add(key, strconv.FormatInt(rv.Interface().(time.Time).Unix(), 10))
+			return nil
+		}
+		return encodeStruct(form, key, rv)
+	default:
+		return fmt.Errorf("icheck: cannot encode field %s of type %s", key, rv.Type())
+	}
+	return nil
+}
+
+// nestKey returns name nested under prefix using bracket notation.
+func nestKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "[" + name + "]"
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// byName sorts map keys by their string form.
+type byName struct {
+	names []string
+	keys  []reflect.Value
+}
+
+func (b byName) Len() int           { return len(b.names) }
+func (b byName) Less(i, j int) bool { return b.names[i] < b.names[j] }
+func (b byName) Swap(i, j int) {
+	b.names[i], b.names[j] = b.names[j], b.names[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
+
+// FormDecodeError is returned by DecodeForm when a value cannot be stored in
+// the field it maps to.
+type FormDecodeError struct {
+	Key   string
+	Value string
+	Type  reflect.Type
+	Err   error
+}
+
+func (e *FormDecodeError) Error() string {
+	return fmt.Sprintf("icheck: cannot decode %s=%q into %s: %v", e.Key, e.Value, e.Type, e.Err)
+}
+
+func (e *FormDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeForm stores the values of form in the struct pointed to by v,
+// following the same "form" tags and conventions as EncodeForm. Keys without
+// a matching field are ignored. A value that does not fit its field, such as
+// "abc" for an int64, makes DecodeForm return a *FormDecodeError.
+func DecodeForm(form *RequestValues, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("icheck: DecodeForm needs a non-nil pointer, got %T", v)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("icheck: cannot decode a form into %s", rv.Type())
+	}
+	return decodeStruct(form, "", rv)
+}
+
+func decodeStruct(form *RequestValues, prefix string, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag, ok := parseFormTag(f)
+		if !ok {
+			if f.Anonymous && f.IsExported() && f.Tag.Get("form") != "-" && indirectType(f.Type).Kind() == reflect.Struct {
+				if err := decodeValue(form, prefix, rv.Field(i), true); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if err := decodeValue(form, nestKey(prefix, tag.name), rv.Field(i), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeValue stores the values found under key in rv. Embedded structs are
+// decoded with the key of their parent.
+func decodeValue(form *RequestValues, key string, rv reflect.Value, embedded bool) error {
+	if !embedded && !form.has(key) {
+		return nil
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeValue(form, key, rv.Elem(), embedded)
+	}
+	if rv.CanAddr() && rv.Addr().Type().Implements(formUnmarshalerType) {
+		return rv.Addr().Interface().(FormUnmarshaler).UnmarshalForm(key, form)
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		if rv.Type() == timeType {
+			// has also matches bracketed sub-keys, which a time has none of.
+			values := form.Get(key)
+			if len(values) == 0 {
+				return &FormDecodeError{Key: key, Type: rv.Type(), Err: fmt.Errorf("expected a Unix timestamp")}
+			}
+			return decodeScalar(key, values[0], rv)
+		}
+		return decodeStruct(form, key, rv)
+	case reflect.Slice:
+		et := indirectType(rv.Type().Elem())
+		if et.Kind() == reflect.Map || et.Kind() == reflect.Struct && et != timeType {
+			indexes := form.subkeys(key)
+			slice := reflect.MakeSlice(rv.Type(), 0, len(indexes))
+			for _, index := range indexes {
+				if _, err := strconv.Atoi(index); err != nil {
+					return &FormDecodeError{Key: key, Value: index, Type: rv.Type(), Err: err}
+				}
+				elem := reflect.New(rv.Type().Elem()).Elem()
+				if err := decodeValue(form, nestKey(key, index), elem, false); err != nil {
+					return err
+				}
+				slice = reflect.Append(slice, elem)
+			}
+			rv.Set(slice)
+			return nil
+		}
+		values := form.Get(key)
+		slice := reflect.MakeSlice(rv.Type(), len(values), len(values))
+		for i, val := range values {
+			if err := decodeScalar(key, val, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return &FormDecodeError{Key: key, Type: rv.Type(), Err: fmt.Errorf("map keys must be strings")}
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+		for _, name := range form.subkeys(key) {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := decodeValue(form, nestKey(key, name), elem, false); err != nil {
+				return err
+			}
+			rv.SetMapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()), elem)
+		}
+		return nil
+	}
+
+	values := form.Get(key)
+	if len(values) == 0 {
+		return nil
+	}
+	return decodeScalar(key, values[0], rv)
+}
+
+func decodeScalar(key, val string, rv reflect.Value) error {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+
+	var err error
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(val)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(val); err == nil {
+			rv.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(val, 10, rv.Type().Bits()); err == nil {
+			rv.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(val, 10, rv.Type().Bits()); err == nil {
+			rv.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var n float64
+		if n, err = strconv.ParseFloat(val, rv.Type().Bits()); err == nil {
+			rv.SetFloat(n)
+		}
+	case reflect.Struct:
+		if rv.Type() != timeType {
+			err = fmt.Errorf("unsupported type")
+			break
+		}
+		var n int64
+		if n, err = strconv.ParseInt(val, 10, 64); err == nil {
+			rv.Set(reflect.ValueOf(time.Unix(n, 0)))
+		}
+	default:
+		err = fmt.Errorf("unsupported type")
+	}
+	if err != nil {
+		return &FormDecodeError{Key: key, Value: val, Type: rv.Type(), Err: err}
+	}
+	return nil
+}
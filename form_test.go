@@ -0,0 +1,128 @@
+package icheck
+
+import (
+	"testing"
+	"time"
+)
+
+type upperName string
+
+func (n upperName) MarshalForm(key string, form *RequestValues) error {
+	form.Add(key, "NAME:"+string(n))
+	return nil
+}
+
+func TestEncodeForm(t *testing.T) {
+	type item struct {
+		SKU string `form:"sku"`
+		Qty int    `form:"qty"`
+	}
+	params := &struct {
+		Params
+		IcheckID []string          `form:"icheck_id"`
+		City     int64             `form:"city,omitempty"`
+		District int64             `form:"district,omitempty"`
+		Verified bool              `form:"verified"`
+		Since    time.Time         `form:"since"`
+		Meta     map[string]string `form:"meta,omitempty"`
+		Items    []item            `form:"items"`
+		Name     upperName         `form:"name"`
+		Ignored  string
+		Skipped  string `form:"-"`
+	}{
+		Params:   Params{AccessToken: "secret"},
+		IcheckID: []string{"i-1", "i-2"},
+		City:     1,
+		Since:    time.Unix(1500000000, 0),
+		Meta:     map[string]string{"z": "1", "a": "2"},
+		Items:    []item{{"A", 1}},
+		Name:     "lan",
+		Ignored:  "x",
+		Skipped:  "y",
+	}
+
+	form, err := EncodeForm(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "icheck_id=i-1&icheck_id=i-2&city=1&verified=false&since=1500000000" +
+		"&meta%5Ba%5D=2&meta%5Bz%5D=1&items%5B0%5D%5Bsku%5D=A&items%5B0%5D%5Bqty%5D=1&name=NAME%3Alan"
+	if got := form.Encode(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestEncodeFormAddressBody(t *testing.T) {
+	form, err := EncodeForm(&AddressBody{Address: "1 Trang Tien", District: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := form.Encode(), "address=1+Trang+Tien&district=5"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestDecodeForm(t *testing.T) {
+	type item struct {
+		SKU string `form:"sku"`
+		Qty int    `form:"qty"`
+	}
+	type params struct {
+		Params
+		IcheckID []string          `form:"icheck_id"`
+		City     int64             `form:"city,omitempty"`
+		Verified *bool             `form:"verified"`
+		Since    time.Time         `form:"since"`
+		Meta     map[string]string `form:"meta"`
+		Items    []item            `form:"items"`
+	}
+
+	form, err := ParseRequestValues("icheck_id=i-1&city=1&icheck_id=i-2&verified=true&since=1500000000" +
+		"&meta%5Ba%5D=2&items%5B0%5D%5Bsku%5D=A&items%5B0%5D%5Bqty%5D=1&items%5B1%5D%5Bsku%5D=B&unknown=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := form.Get("icheck_id"); len(got) != 2 || got[0] != "i-1" || got[1] != "i-2" {
+		t.Fatalf("icheck_id = %v", got)
+	}
+
+	p := &params{}
+	if err := DecodeForm(form, p); err != nil {
+		t.Fatal(err)
+	}
+	if p.City != 1 || p.Verified == nil || !*p.Verified || !p.Since.Equal(time.Unix(1500000000, 0)) {
+		t.Errorf("got %+v", p)
+	}
+	if p.Meta["a"] != "2" || len(p.Items) != 2 || p.Items[0] != (item{"A", 1}) || p.Items[1].SKU != "B" {
+		t.Errorf("got %+v", p)
+	}
+
+	body, _ := EncodeForm(&AddressBody{Address: "1 Trang Tien", City: 1, Email: "a@icheck.vn"})
+	addr := &AddressBody{}
+	if err := DecodeForm(body, addr); err != nil {
+		t.Fatal(err)
+	}
+	if *addr != (AddressBody{Address: "1 Trang Tien", City: 1, Email: "a@icheck.vn"}) {
+		t.Errorf("round trip gave %+v", addr)
+	}
+}
+
+func TestDecodeFormTypeMismatch(t *testing.T) {
+	form, _ := ParseRequestValues("username=lan&ttl=soon")
+	err := DecodeForm(form, &LoginParams{})
+	decodeErr, ok := err.(*FormDecodeError)
+	if !ok || decodeErr.Key != "ttl" || decodeErr.Value != "soon" {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestDecodeFormTimeWithSubkey(t *testing.T) {
+	form, _ := ParseRequestValues("since[x]=1")
+	var v struct {
+		Since time.Time `form:"since"`
+	}
+	err := DecodeForm(form, &v)
+	if decodeErr, ok := err.(*FormDecodeError); !ok || decodeErr.Key != "since" {
+		t.Fatalf("got %v", err)
+	}
+}
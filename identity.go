@@ -0,0 +1,50 @@
+package icheck
+
+import (
+	"fmt"
+	"time"
+)
+
+// Identity is a social account linked to an iCheck user.
+type Identity struct {
+	Provider SocialProvider `json:"provider"`
+	// ProviderUserID is the ID of the user at the provider.
+	ProviderUserID string    `json:"provider_user_id"`
+	Email          string    `json:"email"`
+	Name           string    `json:"name"`
+	LinkedAt       time.Time `json:"linked_at"`
+}
+
+// IdentityResponse ...
+type IdentityResponse struct {
+	Data *Identity `json:"data"`
+}
+
+// IdentityListResponse ...
+type IdentityListResponse struct {
+	Data []*Identity `json:"data"`
+}
+
+// LinkSocialParams ...
+type LinkSocialParams struct {
+	Provider SocialProvider
+	Code     string `form:"code"`
+	// CodeVerifier and RedirectURI must match the authorization request when
+	// it used PKCE.
+	CodeVerifier string `form:"code_verifier,omitempty"`
+	RedirectURI  string `form:"redirect_uri,omitempty"`
+}
+
+// ErrIdentityConflict is returned when linking a social account that
+// already belongs to another iCheck user.
+type ErrIdentityConflict struct {
+	Provider SocialProvider
+	Message  string
+}
+
+func (e *ErrIdentityConflict) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return fmt.Sprintf("icheck: %s account is linked to another user", string(e.Provider))
+}
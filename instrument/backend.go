@@ -0,0 +1,140 @@
+// Package instrument provides an icheck.Backend that reports every call
+// through OpenTelemetry traces and metrics.
+package instrument
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/icheckteam/icheck-go/instrument"
+
+// Config configures the providers used by Backend. Any nil field falls back
+// to the matching global provider registered with the otel package.
+type Config struct {
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+	Propagator     propagation.TextMapPropagator
+}
+
+// Backend wraps an icheck.Backend, creating a client span for each call,
+// propagating the trace context in the request headers and recording call
+// latency and errors.
+type Backend struct {
+	B icheck.Backend
+
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	duration   metric.Float64Histogram
+	errors     metric.Int64Counter
+}
+
+// NewBackend returns a Backend that instruments calls made through b.
+func NewBackend(b icheck.Backend, conf *Config) (*Backend, error) {
+	if conf == nil {
+		conf = &Config{}
+	}
+	tp := conf.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	mp := conf.MeterProvider
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	propagator := conf.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	meter := mp.Meter(instrumentationName)
+	duration, err := meter.Float64Histogram("icheck.client.request.duration",
+		metric.WithDescription("Duration of calls to the iCheck API."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	errors, err := meter.Int64Counter("icheck.client.request.errors",
+		metric.WithDescription("Number of calls to the iCheck API that returned an error."),
+		metric.WithUnit("{call}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Backend{
+		B:          b,
+		tracer:     tp.Tracer(instrumentationName),
+		propagator: propagator,
+		duration:   duration,
+		errors:     errors,
+	}, nil
+}
+
+// Call is the Backend.Call implementation. The caller's params are never
+// modified; the span context travels in a copy.
+func (b *Backend) Call(method, path string, form *icheck.RequestValues, params *icheck.Params, v interface{}) error {
+	p := icheck.Params{}
+	if params != nil {
+		p = *params
+	}
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	route := icheck.RouteTemplate(path)
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", method),
+		attribute.String("url.template", route),
+	}
+
+	ctx, span := b.tracer.Start(ctx, method+" "+route,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	defer span.End()
+
+	info := icheck.CallInfoFromContext(ctx)
+	if info == nil {
+		info = &icheck.CallInfo{}
+		ctx = icheck.WithCallInfo(ctx, info)
+	}
+	p.Context = ctx
+
+	p.Headers = http.Header{}
+	if params != nil {
+		for k, v := range params.Headers {
+			p.Headers[k] = append([]string(nil), v...)
+		}
+	}
+	b.propagator.Inject(ctx, propagation.HeaderCarrier(p.Headers))
+
+	start := time.Now()
+	err := b.B.Call(method, path, form, &p, v)
+	elapsed := time.Since(start)
+
+	if info.StatusCode != 0 {
+		attrs = append(attrs, attribute.Int("http.response.status_code", info.StatusCode))
+	}
+	if info.Attempts > 1 {
+		attrs = append(attrs, attribute.Int("http.request.resend_count", info.Attempts-1))
+	}
+	span.SetAttributes(attrs[2:]...)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		b.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+	b.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+
+	return err
+}
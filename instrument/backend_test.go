@@ -0,0 +1,85 @@
+package instrument
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	icheck "github.com/icheckteam/icheck-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestBackendCall(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{"status":404,"message":"not found"}`))
+	}))
+	defer srv.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	b, err := NewBackend(&icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}, &Config{
+		TracerProvider: tp,
+		MeterProvider:  mp,
+		Propagator:     propagation.TraceContext{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := &icheck.Params{AccessToken: "token"}
+	err = b.Call("GET", "/addresses/42", nil, params, &icheck.AddressResp{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if params.Headers != nil || params.Context != nil {
+		t.Error("params were modified")
+	}
+	if traceparent == "" {
+		t.Error("trace context was not propagated")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("got %d spans, want 1", len(ended))
+	}
+	if name := ended[0].Name(); name != "GET /addresses/{id}" {
+		t.Errorf("span name = %q", name)
+	}
+	want := attribute.Int("http.response.status_code", 200)
+	found := false
+	for _, kv := range ended[0].Attributes() {
+		if kv == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("missing %v in %v", want, ended[0].Attributes())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			names[m.Name] = true
+		}
+	}
+	for _, name := range []string{"icheck.client.request.duration", "icheck.client.request.errors"} {
+		if !names[name] {
+			t.Errorf("metric %s was not recorded", name)
+		}
+	}
+}
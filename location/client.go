@@ -14,6 +14,15 @@ type Client struct {
 
 // Me get current user
 func (c *Client) List(params url.Values) (*icheck.LocationsResponse, error) {
+	env, err := c.ListEnvelope(params)
+	if err != nil {
+		return nil, err
+	}
+	return &icheck.LocationsResponse{Status: env.Status, Data: env.Data}, nil
+}
+
+// ListEnvelope is List returning the whole response envelope.
+func (c *Client) ListEnvelope(params url.Values) (*icheck.Envelope[[]map[string]interface{}], error) {
 	body := &icheck.RequestValues{}
 
 	if params.Get("parent") != "" {
@@ -25,20 +34,19 @@ func (c *Client) List(params url.Values) (*icheck.LocationsResponse, error) {
 	} else {
 		body.Add("type", "city")
 	}
-	resp := &icheck.LocationsResponse{}
-	err := c.B.Call("GET", "/locations", body, nil, resp)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
+	return icheck.CallEnvelope[[]map[string]interface{}](c.B, "GET", "/locations", body, nil)
 }
 
 // Me get current user
 func (c *Client) Get(id string) (*icheck.LocationResponse, error) {
-	resp := &icheck.LocationResponse{}
-	err := c.B.Call("GET", fmt.Sprintf("/locations/%v", id), nil, nil, resp)
+	env, err := c.GetEnvelope(id)
 	if err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &icheck.LocationResponse{Status: env.Status, Data: env.Data}, nil
+}
+
+// GetEnvelope is Get returning the whole response envelope.
+func (c *Client) GetEnvelope(id string) (*icheck.Envelope[map[string]interface{}], error) {
+	return icheck.CallEnvelope[map[string]interface{}](c.B, "GET", fmt.Sprintf("/locations/%v", id), nil, nil)
 }
@@ -0,0 +1,76 @@
+package icheck
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MFAEnrollment is a TOTP secret being set up by a user. It becomes active
+// once a code generated from it is verified.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	// URI is the otpauth:// URI of the secret, to show as a QR code.
+	URI string `json:"uri"`
+}
+
+// MFAEnrollmentResponse ...
+type MFAEnrollmentResponse struct {
+	Data *MFAEnrollment `json:"data"`
+}
+
+// RecoveryCodes are single use codes that replace a TOTP code when the
+// device of the user is lost.
+type RecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
+
+// RecoveryCodesResponse ...
+type RecoveryCodesResponse struct {
+	Data *RecoveryCodes `json:"data"`
+}
+
+// MFACodeParams ...
+type MFACodeParams struct {
+	// Code is a TOTP code or a recovery code.
+	Code string `form:"code"`
+}
+
+// CompleteMFAParams ...
+type CompleteMFAParams struct {
+	ChallengeID string `form:"challenge_id"`
+	Code        string `form:"code"`
+}
+
+// MFAChallenge is returned by a login that needs a second factor.
+type MFAChallenge struct {
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ErrMFARequired is returned by a login when the user has two-factor
+// authentication enabled. The login is finished by sending a code for
+// Challenge.
+type ErrMFARequired struct {
+	Challenge *MFAChallenge
+}
+
+func (e *ErrMFARequired) Error() string {
+	return fmt.Sprintf("icheck: two-factor code required for challenge %s", e.Challenge.ID)
+}
+
+// ErrNoAccessToken is returned when a login succeeds without returning an
+// access token.
+var ErrNoAccessToken = errors.New("icheck: login response has no access token")
+
+// Token returns the access token of r. It returns *ErrMFARequired when the
+// API asks for a second factor instead.
+func (r *LoginResponse) Token() (*AccessToken, error) {
+	if r.MFA != nil {
+		return nil, &ErrMFARequired{Challenge: r.MFA}
+	}
+	if r.Data == nil {
+		return nil, ErrNoAccessToken
+	}
+	return r.Data, nil
+}
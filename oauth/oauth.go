@@ -0,0 +1,211 @@
+// Package oauth logs users in with Google, Facebook, Apple or Zalo using the
+// OAuth2 authorization code flow with PKCE. The code returned to the app is
+// exchanged for an icheck.AccessToken through the /auth/{provider} API.
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	icheck "github.com/icheckteam/icheck-go"
+	"github.com/icheckteam/icheck-go/account"
+)
+
+// ErrStateMismatch is returned when the state of a callback does not match
+// the one of the flow, e.g. because of a forged request.
+var ErrStateMismatch = errors.New("icheck: oauth state mismatch")
+
+// ErrMissingCode is returned for callbacks carrying neither a code nor an
+// error.
+var ErrMissingCode = errors.New("icheck: oauth callback has no code")
+
+// ErrAuthorization is returned when the provider reports an error in the
+// callback, e.g. because the user denied access.
+type ErrAuthorization struct {
+	Code        string
+	Description string
+}
+
+func (e *ErrAuthorization) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("icheck: oauth authorization failed: %s: %s", e.Code, e.Description)
+	}
+	return fmt.Sprintf("icheck: oauth authorization failed: %s", e.Code)
+}
+
+// endpoint describes the authorization endpoint of a provider.
+type endpoint struct {
+	URL string
+	// ClientIDParam is the name of the client ID parameter.
+	ClientIDParam string
+	Scopes        []string
+	// Extra parameters always sent.
+	Extra url.Values
+}
+
+var endpoints = map[icheck.SocialProvider]endpoint{
+	icheck.ProviderGoogle: {
+		URL:           "https://accounts.google.com/o/oauth2/v2/auth",
+		ClientIDParam: "client_id",
+		Scopes:        []string{"openid", "email", "profile"},
+	},
+	icheck.ProviderFacebook: {
+		URL:           "https://www.facebook.com/v19.0/dialog/oauth",
+		ClientIDParam: "client_id",
+		Scopes:        []string{"public_profile", "email"},
+	},
+	icheck.ProviderApple: {
+		URL:           "https://appleid.apple.com/auth/authorize",
+		ClientIDParam: "client_id",
+		Scopes:        []string{"name", "email"},
+		// Apple posts the callback when scopes are requested.
+		Extra: url.Values{"response_mode": {"form_post"}},
+	},
+	icheck.ProviderZalo: {
+		URL:           "https://oauth.zaloapp.com/v4/permission",
+		ClientIDParam: "app_id",
+	},
+}
+
+// Config is the app registration with a provider.
+type Config struct {
+	Provider    icheck.SocialProvider
+	ClientID    string
+	RedirectURL string
+	// Scopes defaults to the scopes needed to fill an icheck.User.
+	Scopes []string
+}
+
+// Flow is the state of an authorization in progress. It must be kept, e.g.
+// in the user's session, between AuthCodeURL and Exchange.
+type Flow struct {
+	Provider    icheck.SocialProvider
+	RedirectURL string
+	State       string
+	Verifier    string
+}
+
+// AuthCodeURL returns the URL to send the user to, and the flow to keep
+// until the callback.
+func (c *Config) AuthCodeURL() (string, *Flow, error) {
+	ep, ok := endpoints[c.Provider]
+	if !ok {
+		return "", nil, &icheck.ErrUnknownProvider{Provider: c.Provider}
+	}
+	state, err := randomString()
+	if err != nil {
+		return "", nil, err
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return "", nil, err
+	}
+
+	v := url.Values{}
+	for k, vs := range ep.Extra {
+		v[k] = vs
+	}
+	v.Set(ep.ClientIDParam, c.ClientID)
+	v.Set("redirect_uri", c.RedirectURL)
+	v.Set("response_type", "code")
+	v.Set("state", state)
+	v.Set("code_challenge", challenge(verifier))
+	v.Set("code_challenge_method", "S256")
+	scopes := c.Scopes
+	if scopes == nil {
+		scopes = ep.Scopes
+	}
+	if len(scopes) > 0 {
+		v.Set("scope", strings.Join(scopes, " "))
+	}
+
+	flow := &Flow{
+		Provider:    c.Provider,
+		RedirectURL: c.RedirectURL,
+		State:       state,
+		Verifier:    verifier,
+	}
+	return ep.URL + "?" + v.Encode(), flow, nil
+}
+
+// Code validates the parameters of a callback, from the query string or,
+// for Apple, the posted form, and returns the authorization code.
+func (f *Flow) Code(callback url.Values) (string, error) {
+	if e := callback.Get("error"); e != "" {
+		return "", &ErrAuthorization{Code: e, Description: callback.Get("error_description")}
+	}
+	if subtle.ConstantTimeCompare([]byte(callback.Get("state")), []byte(f.State)) != 1 {
+		return "", ErrStateMismatch
+	}
+	code := callback.Get("code")
+	if code == "" {
+		return "", ErrMissingCode
+	}
+	return code, nil
+}
+
+// Client is used to exchange authorization codes through the /auth APIs.
+type Client struct {
+	B icheck.Backend
+}
+
+// Exchange validates callback against flow and exchanges its code for an
+// access token valid for ttl seconds, or the API default if ttl is zero.
+func (c *Client) Exchange(flow *Flow, callback url.Values, ttl int64) (*icheck.AccessToken, error) {
+	code, err := flow.Code(callback)
+	if err != nil {
+		return nil, err
+	}
+	data := flow.params(code)
+	data.TTL = ttl
+	a := &account.Client{B: c.B}
+	return a.LoginWithSocial(data)
+}
+
+// Link validates callback against flow and links the account it authorizes
+// to the user of params.
+func (c *Client) Link(flow *Flow, callback url.Values, params *icheck.Params) (*icheck.Identity, error) {
+	code, err := flow.Code(callback)
+	if err != nil {
+		return nil, err
+	}
+	a := &account.Client{B: c.B}
+	return a.LinkSocial(&icheck.LinkSocialParams{
+		Provider:     flow.Provider,
+		Code:         code,
+		CodeVerifier: flow.Verifier,
+		RedirectURI:  flow.RedirectURL,
+	}, params)
+}
+
+// params returns the params exchanging code, obtained through f.
+func (f *Flow) params(code string) *icheck.LoginSocialParams {
+	return &icheck.LoginSocialParams{
+		Provider:     f.Provider,
+		Code:         code,
+		CodeVerifier: f.Verifier,
+		RedirectURI:  f.RedirectURL,
+	}
+}
+
+// randomString returns 32 random bytes, base64url encoded, as required of
+// PKCE verifiers.
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// challenge returns the S256 PKCE challenge of verifier.
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
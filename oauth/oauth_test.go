@@ -0,0 +1,97 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestAuthCodeURLAndExchange(t *testing.T) {
+	conf := &Config{Provider: icheck.ProviderZalo, ClientID: "app", RedirectURL: "https://example.com/cb"}
+	raw, flow, err := conf.AuthCodeURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("app_id") != "app" || q.Get("state") != flow.State || q.Get("code_challenge") != challenge(flow.Verifier) {
+		t.Errorf("unexpected authorization URL %s", raw)
+	}
+
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/auth/zalo" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		r.ParseForm()
+		form = r.PostForm
+		w.Write([]byte(`{"status":200,"data":{"id":"tok"}}`))
+	}))
+	defer srv.Close()
+	c := &Client{B: &icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}}
+
+	if _, err := c.Exchange(flow, url.Values{"state": {"forged"}, "code": {"c"}}, 0); err != ErrStateMismatch {
+		t.Errorf("forged state: err = %v", err)
+	}
+	_, err = c.Exchange(flow, url.Values{"error": {"access_denied"}}, 0)
+	if e, ok := err.(*ErrAuthorization); !ok || e.Code != "access_denied" {
+		t.Errorf("denied: err = %v", err)
+	}
+
+	token, err := c.Exchange(flow, url.Values{"state": {flow.State}, "code": {"c"}}, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.ID != "tok" {
+		t.Errorf("token ID = %q", token.ID)
+	}
+	if form.Get("code") != "c" || form.Get("code_verifier") != flow.Verifier || form.Get("redirect_uri") != conf.RedirectURL {
+		t.Errorf("form = %v", form)
+	}
+}
+
+func TestAuthCodeURLUnknownProvider(t *testing.T) {
+	conf := &Config{Provider: "myspace"}
+	_, _, err := conf.AuthCodeURL()
+	if err == nil || !strings.Contains(err.Error(), "myspace") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestLinkSendsVerifier(t *testing.T) {
+	conf := &Config{Provider: icheck.ProviderGoogle, ClientID: "app", RedirectURL: "https://example.com/cb"}
+	_, flow, err := conf.AuthCodeURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/account/identities/google" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		r.ParseForm()
+		form = r.PostForm
+		w.Write([]byte(`{"status":200,"data":{"provider":"google","provider_user_id":"g1"}}`))
+	}))
+	defer srv.Close()
+	c := &Client{B: &icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}}
+
+	id, err := c.Link(flow, url.Values{"state": {flow.State}, "code": {"c"}}, &icheck.Params{AccessToken: "tok"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.ProviderUserID != "g1" {
+		t.Errorf("identity = %+v", id)
+	}
+	if form.Get("code_verifier") != flow.Verifier || form.Get("redirect_uri") != conf.RedirectURL {
+		t.Errorf("form = %v", form)
+	}
+}
@@ -0,0 +1,51 @@
+package icheck
+
+import "time"
+
+// OTPChallenge describes a one-time code sent to a phone number.
+type OTPChallenge struct {
+	ID        string    `json:"id"`
+	Phone     string    `json:"phone"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// ResendAt is the earliest time a new code may be requested.
+	ResendAt time.Time `json:"resend_at"`
+}
+
+// OTPChallengeResponse ...
+type OTPChallengeResponse struct {
+	Data *OTPChallenge `json:"data"`
+}
+
+// OTPLoginParams logs in, or signs up, the owner of Phone. Name and Password
+// are used when the phone number has no account yet.
+type OTPLoginParams struct {
+	Phone    string
+	Code     string
+	Name     string `form:"name,omitempty"`
+	Password string `form:"password,omitempty"`
+	TTL      int64  `form:"ttl,omitempty"`
+}
+
+// OTPResetPasswordParams ...
+type OTPResetPasswordParams struct {
+	Phone    string
+	Code     string
+	Password string `form:"password,omitempty"`
+}
+
+// OTPChangePhoneParams moves the current user to the new phone Phone.
+type OTPChangePhoneParams struct {
+	Phone    string
+	Code     string
+	Password string `form:"password,omitempty"`
+}
+
+// ResetPasswordResponse holds the user whose password was reset.
+type ResetPasswordResponse struct {
+	Data *User `json:"data"`
+}
+
+// ChangePhoneResponse
+type ChangePhoneResponse struct {
+	Data *PhoneChange `json:"data"`
+}
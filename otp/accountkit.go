@@ -0,0 +1,58 @@
+package otp
+
+import (
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// AccountKitClient has the methods of accountkit.Client, so callers of the
+// defunct Account Kit flow only have to change how the client is built.
+// The Code of the Account Kit params is passed to the provider without a
+// phone number, so the provider must read the phone from the code. Provider
+// defaults to FirebaseProvider, which does so with the Firebase ID token
+// obtained on the device.
+type AccountKitClient struct {
+	Client
+}
+
+// client returns c.Client with its provider defaulting to FirebaseProvider.
+func (c *AccountKitClient) client() *Client {
+	client := c.Client
+	if client.Provider == nil {
+		client.Provider = &FirebaseProvider{}
+	}
+	return &client
+}
+
+// Login is accountkit.Client.Login.
+func (c *AccountKitClient) Login(params *icheck.AccountKitLoginParams) (*icheck.AccessToken, error) {
+	return c.client().Login(&icheck.OTPLoginParams{
+		Code:     params.Code,
+		Name:     params.Name,
+		Password: params.Password,
+		TTL:      params.TTL,
+	})
+}
+
+// ResetPassword is accountkit.Client.ResetPassword.
+func (c *AccountKitClient) ResetPassword(params *icheck.AccountKitResetPasswordParams) (*icheck.AccountKitResetPasswordResponse, error) {
+	user, err := c.client().ResetPassword(&icheck.OTPResetPasswordParams{
+		Code:     params.Code,
+		Password: params.Password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &icheck.AccountKitResetPasswordResponse{Data: user}, nil
+}
+
+// ChangePhone is accountkit.Client.ChangePhone.
+func (c *AccountKitClient) ChangePhone(data *icheck.AccountKitChangePhoneParams, params *icheck.Params) (*icheck.AccountKitChangePhoneResponse, error) {
+	change, err := c.client().ChangePhone(&icheck.OTPChangePhoneParams{
+		Code:     data.Code,
+		Password: data.Password,
+	}, params)
+	if err != nil {
+		return nil, err
+	}
+	return &icheck.AccountKitChangePhoneResponse{Data: change}, nil
+}
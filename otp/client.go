@@ -0,0 +1,98 @@
+// Package otp logs users in with one-time codes sent to their phone. It
+// replaces the accountkit package, whose Facebook Account Kit service has
+// been shut down. Client logs in with the same icheck.AccessToken, and
+// AccountKitClient keeps the exact signatures of accountkit.Client for
+// callers that cannot be rewritten yet.
+package otp
+
+import (
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// Client is used to invoke /otp APIs.
+type Client struct {
+	B icheck.Backend
+	// Provider defaults to an SMSProvider using B.
+	Provider Provider
+}
+
+func (c *Client) provider() Provider {
+	if c.Provider == nil {
+		return &SMSProvider{B: c.B}
+	}
+	return c.Provider
+}
+
+// Send sends a code to phone through the provider.
+func (c *Client) Send(phone string) (*icheck.OTPChallenge, error) {
+	return c.provider().Send(phone)
+}
+
+// Login logs in the owner of the phone number, creating the account if
+// needed. It returns *icheck.ErrMFARequired when the user must also enter a
+// two-factor code, to be sent with account.Client.CompleteMFA.
+func (c *Client) Login(params *icheck.OTPLoginParams) (*icheck.AccessToken, error) {
+	body, err := c.body(params.Phone, params.Code, params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &icheck.LoginResponse{}
+
+	err = c.B.Call("POST", "/otp/login", body, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Token()
+}
+
+// ResetPassword sets the password of the owner of the phone number.
+func (c *Client) ResetPassword(params *icheck.OTPResetPasswordParams) (*icheck.User, error) {
+	body, err := c.body(params.Phone, params.Code, params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &icheck.ResetPasswordResponse{}
+
+	err = c.B.Call("POST", "/otp/reset-password", body, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// ChangePhone moves the current user to a new phone number.
+func (c *Client) ChangePhone(data *icheck.OTPChangePhoneParams, params *icheck.Params) (*icheck.PhoneChange, error) {
+	body, err := c.body(data.Phone, data.Code, data)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &icheck.ChangePhoneResponse{}
+
+	err = c.B.Call("POST", "/otp/change-phone", body, params, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// body returns the provider's credential followed by the tagged fields of
+// params.
+func (c *Client) body(phone, code string, params interface{}) (*icheck.RequestValues, error) {
+	body, err := c.provider().Credential(phone, code)
+	if err != nil {
+		return nil, err
+	}
+	extra, err := icheck.EncodeForm(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"name", "password", "ttl"} {
+		for _, v := range extra.Get(key) {
+			body.Add(key, v)
+		}
+	}
+	return body, nil
+}
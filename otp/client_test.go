@@ -0,0 +1,129 @@
+package otp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestLoginWithTestProvider(t *testing.T) {
+	var form map[string][]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/otp/login" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		r.ParseForm()
+		form = r.PostForm
+		w.Write([]byte(`{"status":200,"data":{"id":"abc"}}`))
+	}))
+	defer ts.Close()
+
+	c := &Client{B: &icheck.BackendConfiguration{URL: ts.URL, HTTPClient: ts.Client()}, Provider: &TestProvider{}}
+
+	if _, err := c.Login(&icheck.OTPLoginParams{Phone: "+84977465849", Code: "123456"}); err != ErrInvalidCode {
+		t.Fatalf("login before send: err = %v, want ErrInvalidCode", err)
+	}
+	if _, err := c.Send("0977 465 849"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Login(&icheck.OTPLoginParams{Phone: "+84977465849", Code: "000000"}); err != ErrInvalidCode {
+		t.Fatalf("wrong code: err = %v, want ErrInvalidCode", err)
+	}
+
+	token, err := c.Login(&icheck.OTPLoginParams{Phone: "+84977465849", Code: "123456", Name: "An", TTL: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.ID != "abc" {
+		t.Errorf("token ID = %q", token.ID)
+	}
+	want := map[string]string{"provider": "test", "phone": "0977465849", "code": "123456", "name": "An", "ttl": "60"}
+	for k, v := range want {
+		if got := form[k]; len(got) != 1 || got[0] != v {
+			t.Errorf("form[%q] = %v, want %q", k, got, v)
+		}
+	}
+	if _, ok := form["password"]; ok {
+		t.Errorf("form has empty password")
+	}
+}
+
+func TestFirebaseProvider(t *testing.T) {
+	p := &FirebaseProvider{}
+	if _, err := p.Send("0977465849"); err != ErrSendNotSupported {
+		t.Errorf("Send: err = %v", err)
+	}
+	form, err := p.Credential("", "id-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := form.Get("id_token"); len(got) != 1 || got[0] != "id-token" {
+		t.Errorf("id_token = %v", got)
+	}
+}
+
+func TestLoginMFARequired(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":200,"mfa":{"id":"ch1"}}`))
+	}))
+	defer ts.Close()
+
+	p := &TestProvider{}
+	p.Send("0977465849")
+	c := &Client{B: &icheck.BackendConfiguration{URL: ts.URL, HTTPClient: ts.Client()}, Provider: p}
+	_, err := c.Login(&icheck.OTPLoginParams{Phone: "0977465849", Code: "123456"})
+	if mfa, ok := err.(*icheck.ErrMFARequired); !ok || mfa.Challenge.ID != "ch1" {
+		t.Errorf("err = %v, want *icheck.ErrMFARequired", err)
+	}
+}
+
+func TestAccountKitClientAndDefaultProvider(t *testing.T) {
+	var paths []string
+	var form map[string][]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		r.ParseForm()
+		form = r.PostForm
+		switch r.URL.Path {
+		case "/otp/send":
+			w.Write([]byte(`{"status":200,"data":{"id":"ch1","phone":"0977465849"}}`))
+		case "/otp/reset-password":
+			w.Write([]byte(`{"status":200,"data":{"id":7}}`))
+		default:
+			w.Write([]byte(`{"status":200,"data":{"id":"tok"}}`))
+		}
+	}))
+	defer ts.Close()
+	b := &icheck.BackendConfiguration{URL: ts.URL, HTTPClient: ts.Client()}
+
+	challenge, err := (&Client{B: b}).Send("0977465849")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if challenge.ID != "ch1" {
+		t.Errorf("challenge = %+v", challenge)
+	}
+
+	// The Account Kit code carries no phone: the default provider reads it
+	// from the Firebase ID token.
+	ak := &AccountKitClient{Client{B: b}}
+	token, err := ak.Login(&icheck.AccountKitLoginParams{Code: "id-token", TTL: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.ID != "tok" || form["id_token"][0] != "id-token" || form["ttl"][0] != "60" {
+		t.Errorf("token = %+v, form = %v", token, form)
+	}
+	reset, err := ak.ResetPassword(&icheck.AccountKitResetPasswordParams{Code: "id-token", Password: "secret123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reset.Data == nil || reset.Data.ID != 7 {
+		t.Errorf("reset = %+v", reset)
+	}
+	if form["provider"][0] != "firebase" {
+		t.Errorf("form = %v", form)
+	}
+}
@@ -0,0 +1,141 @@
+package otp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// ErrSendNotSupported is returned by providers whose codes are delivered on
+// the device rather than requested through the API.
+var ErrSendNotSupported = errors.New("icheck: provider does not send codes")
+
+// ErrInvalidCode is returned when a code is rejected before reaching the API.
+var ErrInvalidCode = errors.New("icheck: invalid verification code")
+
+// Provider verifies that a user owns a phone number.
+type Provider interface {
+	// Send sends a code to phone.
+	Send(phone string) (*icheck.OTPChallenge, error)
+	// Credential returns the form fields proving to the API that the user
+	// received code on phone.
+	Credential(phone, code string) (*icheck.RequestValues, error)
+}
+
+// SMSProvider uses the SMS codes sent by iCheck.
+type SMSProvider struct {
+	B icheck.Backend
+}
+
+// Send is the Provider.Send implementation.
+func (p *SMSProvider) Send(phone string) (*icheck.OTPChallenge, error) {
+	phone, err := icheck.LocalPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	body := &icheck.RequestValues{}
+	body.Add("phone", phone)
+
+	resp := &icheck.OTPChallengeResponse{}
+	err = p.B.Call("POST", "/otp/send", body, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Credential is the Provider.Credential implementation.
+func (p *SMSProvider) Credential(phone, code string) (*icheck.RequestValues, error) {
+	phone, err := icheck.LocalPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	form := &icheck.RequestValues{}
+	form.Add("provider", "sms")
+	form.Add("phone", phone)
+	form.Add("code", code)
+	return form, nil
+}
+
+// FirebaseProvider uses Firebase phone authentication. The app signs the
+// user in with the Firebase SDK and passes the resulting ID token as the
+// code; the API reads the phone number from the token.
+type FirebaseProvider struct{}
+
+// Send is the Provider.Send implementation. Firebase sends codes from the
+// app, so it always returns ErrSendNotSupported.
+func (p *FirebaseProvider) Send(phone string) (*icheck.OTPChallenge, error) {
+	return nil, ErrSendNotSupported
+}
+
+// Credential is the Provider.Credential implementation.
+func (p *FirebaseProvider) Credential(phone, idToken string) (*icheck.RequestValues, error) {
+	if idToken == "" {
+		return nil, ErrInvalidCode
+	}
+	form := &icheck.RequestValues{}
+	form.Add("provider", "firebase")
+	form.Add("id_token", idToken)
+	return form, nil
+}
+
+// TestProvider is a local provider for tests and the sandbox. It sends no
+// SMS: every phone number receives Code, and codes are checked locally.
+type TestProvider struct {
+	// Code is the code every phone receives. Defaults to "123456".
+	Code string
+
+	mu   sync.Mutex
+	sent map[string]bool
+}
+
+// Send is the Provider.Send implementation.
+func (p *TestProvider) Send(phone string) (*icheck.OTPChallenge, error) {
+	phone, err := icheck.LocalPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.sent == nil {
+		p.sent = make(map[string]bool)
+	}
+	p.sent[phone] = true
+
+	now := time.Now()
+	return &icheck.OTPChallenge{
+		ID:        fmt.Sprintf("test-%s", phone),
+		Phone:     phone,
+		ExpiresAt: now.Add(5 * time.Minute),
+		ResendAt:  now,
+	}, nil
+}
+
+// Credential is the Provider.Credential implementation. It fails with
+// ErrInvalidCode unless a code was sent to phone and code matches it.
+func (p *TestProvider) Credential(phone, code string) (*icheck.RequestValues, error) {
+	phone, err := icheck.LocalPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	want := p.Code
+	if want == "" {
+		want = "123456"
+	}
+
+	p.mu.Lock()
+	sent := p.sent[phone]
+	p.mu.Unlock()
+	if !sent || code != want {
+		return nil, ErrInvalidCode
+	}
+
+	form := &icheck.RequestValues{}
+	form.Add("provider", "test")
+	form.Add("phone", phone)
+	form.Add("code", code)
+	return form, nil
+}
@@ -2,8 +2,10 @@ package icheck
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // RequestValues is a collection of values that can be submitted along with a
@@ -32,6 +34,32 @@ func (f *RequestValues) Encode() string {
 	return buf.String()
 }
 
+// ParseRequestValues parses a URL encoded form or query string
+// ("bar=baz&foo=quux") into RequestValues, keeping duplicate keys in the
+// order they appear. It is the inverse of Encode.
+func ParseRequestValues(s string) (*RequestValues, error) {
+	f := &RequestValues{}
+	for _, pair := range strings.Split(s, "&") {
+		if pair == "" {
+			continue
+		}
+		key, val := pair, ""
+		if i := strings.IndexByte(pair, '='); i >= 0 {
+			key, val = pair[:i], pair[i+1:]
+		}
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, err
+		}
+		val, err = url.QueryUnescape(val)
+		if err != nil {
+			return nil, err
+		}
+		f.Add(key, val)
+	}
+	return f, nil
+}
+
 // Empty returns true if no parameters have been set.
 func (f *RequestValues) Empty() bool {
 	return len(f.values) == 0
@@ -66,6 +94,38 @@ func (f *RequestValues) Get(key string) []string {
 	return results
 }
 
+// has reports whether key, or any key nested under it in bracket notation,
+// is set.
+func (f *RequestValues) has(key string) bool {
+	for _, v := range f.values {
+		if v.Key == key || strings.HasPrefix(v.Key, key+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// subkeys returns the distinct names nested directly under key, in the order
+// they were added. For "meta[a]=1&meta[b][c]=2" the subkeys of "meta" are
+// "a" and "b".
+func (f *RequestValues) subkeys(key string) []string {
+	var results []string
+	seen := make(map[string]bool)
+	for _, v := range f.values {
+		if !strings.HasPrefix(v.Key, key+"[") {
+			continue
+		}
+		rest := v.Key[len(key)+1:]
+		i := strings.IndexByte(rest, ']')
+		if i < 0 || seen[rest[:i]] {
+			continue
+		}
+		seen[rest[:i]] = true
+		results = append(results, rest[:i])
+	}
+	return results
+}
+
 // ToValues converts an instance of RequestValues into an instance of
 // url.Values. This can be useful in cases where it's useful to make an
 // unordered comparison of two sets of request values.
@@ -102,6 +162,11 @@ type formValue struct {
 // of any *Params structure.
 type Params struct {
 	AccessToken string
+	// Context is attached to the outgoing HTTP request. It may be used to
+	// cancel the request or to carry tracing information.
+	Context context.Context
 	// Headers may be used to provide extra header lines on the HTTP request.
 	Headers http.Header
+	// Body, if set, is sent as the request body instead of the form.
+	Body RequestBody
 }
@@ -0,0 +1,70 @@
+package icheck
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// MinPasswordLength is the shortest password accepted by ValidatePassword.
+const MinPasswordLength = 8
+
+// ChangePasswordParams ...
+type ChangePasswordParams struct {
+	OldPassword string `form:"old_password"`
+	NewPassword string `form:"new_password"`
+}
+
+// ForgotPasswordParams ...
+type ForgotPasswordParams struct {
+	// Username is the phone number or email of the account.
+	Username string `form:"username"`
+	// Channel picks where the reset code is sent. Defaults to the phone for
+	// phone usernames and the email otherwise.
+	Channel VerificationChannel `form:"channel,omitempty"`
+}
+
+// ResetPasswordParams ...
+type ResetPasswordParams struct {
+	Username string `form:"username"`
+	Code     string `form:"code"`
+	Password string `form:"password"`
+}
+
+// ValidatePassword checks that password is strong enough: at least
+// MinPasswordLength characters, mixing letters and digits. Failures are
+// reported as an *ErrBadRequest, like the validation errors of the API.
+func ValidatePassword(password string) error {
+	var rules []Rule
+	if len([]rune(password)) < MinPasswordLength {
+		rules = append(rules, Rule{
+			Rule:    "minLength",
+			Message: fmt.Sprintf("Password must be at least %d characters long", MinPasswordLength),
+		})
+	}
+
+	var letter, digit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			letter = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	if !letter || !digit {
+		rules = append(rules, Rule{
+			Rule:    "strength",
+			Message: "Password must contain both letters and digits",
+		})
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+	return &ErrBadRequest{
+		Status:            400,
+		RError:            "E_VALIDATION",
+		Summary:           "1 attribute is invalid",
+		InvalidAttributes: map[string][]Rule{"password": rules},
+	}
+}
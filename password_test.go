@@ -0,0 +1,17 @@
+package icheck
+
+import "testing"
+
+func TestValidatePassword(t *testing.T) {
+	if err := ValidatePassword("icheck2017"); err != nil {
+		t.Errorf("strong password rejected: %v", err)
+	}
+
+	for _, password := range []string{"", "short1", "onlyletters", "1234567890"} {
+		err := ValidatePassword(password)
+		badRequest, ok := err.(*ErrBadRequest)
+		if !ok || len(badRequest.InvalidAttributes["password"]) == 0 || badRequest.Error() == "" {
+			t.Errorf("ValidatePassword(%q) = %v", password, err)
+		}
+	}
+}
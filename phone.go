@@ -0,0 +1,60 @@
+package icheck
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPhone is returned for strings that are not Vietnamese phone
+// numbers.
+var ErrInvalidPhone = errors.New("icheck: invalid Vietnamese phone number")
+
+var phoneSeparators = strings.NewReplacer(" ", "", ".", "", "-", "", "(", "", ")", "")
+
+// phoneDigits returns the national significant number of phone, i.e. its
+// digits without the 0 or +84 prefix.
+func phoneDigits(phone string) (string, error) {
+	phone = phoneSeparators.Replace(strings.TrimSpace(phone))
+	switch {
+	case strings.HasPrefix(phone, "+84"):
+		phone = phone[3:]
+	case strings.HasPrefix(phone, "0084"):
+		phone = phone[4:]
+	case strings.HasPrefix(phone, "84") && len(phone) >= 11:
+		phone = phone[2:]
+	case strings.HasPrefix(phone, "0"):
+		phone = phone[1:]
+	default:
+		return "", ErrInvalidPhone
+	}
+	// Mobile numbers have 9 digits, landlines 10.
+	if len(phone) < 9 || len(phone) > 10 || phone[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	for _, c := range phone {
+		if c < '0' || c > '9' {
+			return "", ErrInvalidPhone
+		}
+	}
+	return phone, nil
+}
+
+// NormalizePhone returns phone in international format, e.g. "+84977465849"
+// for "0977 465 849".
+func NormalizePhone(phone string) (string, error) {
+	digits, err := phoneDigits(phone)
+	if err != nil {
+		return "", err
+	}
+	return "+84" + digits, nil
+}
+
+// LocalPhone returns phone in national format, e.g. "0977465849" for
+// "+84 977 465 849". This is the format the API expects in usernames.
+func LocalPhone(phone string) (string, error) {
+	digits, err := phoneDigits(phone)
+	if err != nil {
+		return "", err
+	}
+	return "0" + digits, nil
+}
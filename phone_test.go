@@ -0,0 +1,29 @@
+package icheck
+
+import "testing"
+
+func TestPhoneNormalization(t *testing.T) {
+	tests := []struct {
+		in, international, local string
+	}{
+		{"0977465849", "+84977465849", "0977465849"},
+		{"+84 977 465 849", "+84977465849", "0977465849"},
+		{"84977465849", "+84977465849", "0977465849"},
+		{"0084.977.465.849", "+84977465849", "0977465849"},
+		{"(024) 3825-1234", "+842438251234", "02438251234"},
+	}
+	for _, tt := range tests {
+		if got, err := NormalizePhone(tt.in); err != nil || got != tt.international {
+			t.Errorf("NormalizePhone(%q) = %q, %v; want %q", tt.in, got, err, tt.international)
+		}
+		if got, err := LocalPhone(tt.in); err != nil || got != tt.local {
+			t.Errorf("LocalPhone(%q) = %q, %v; want %q", tt.in, got, err, tt.local)
+		}
+	}
+
+	for _, in := range []string{"", "lan@icheck.vn", "12345", "0977abc849", "00977465849"} {
+		if _, err := NormalizePhone(in); err != ErrInvalidPhone {
+			t.Errorf("NormalizePhone(%q) = %v, want ErrInvalidPhone", in, err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package icheck
+
+import "time"
+
+// DataExportStatus is the state of a DataExport.
+type DataExportStatus string
+
+const (
+	DataExportPending DataExportStatus = "pending"
+	DataExportReady   DataExportStatus = "ready"
+	DataExportFailed  DataExportStatus = "failed"
+)
+
+// DataExport is an archive of the personal data of a user: profile,
+// addresses and sessions. Archives are built in the background; URL is set
+// once Status is DataExportReady.
+type DataExport struct {
+	ID        string           `json:"id"`
+	Status    DataExportStatus `json:"status"`
+	URL       string           `json:"url"`
+	CreatedAt time.Time        `json:"created_at"`
+	// ExpiresAt is when URL stops working.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DataExportResponse ...
+type DataExportResponse struct {
+	Data *DataExport `json:"data"`
+}
+
+// AccountDeletionStatus is the state of an AccountDeletion.
+type AccountDeletionStatus string
+
+const (
+	AccountDeletionScheduled AccountDeletionStatus = "scheduled"
+	AccountDeletionCancelled AccountDeletionStatus = "cancelled"
+	AccountDeletionCompleted AccountDeletionStatus = "completed"
+)
+
+// AccountDeletion is a request to erase a user. The account is deleted at
+// DeleteAt, at the end of a grace period during which it can be cancelled.
+type AccountDeletion struct {
+	UserID      int                   `json:"user_id"`
+	Status      AccountDeletionStatus `json:"status"`
+	RequestedAt time.Time             `json:"requested_at"`
+	DeleteAt    time.Time             `json:"delete_at"`
+}
+
+// AccountDeletionResponse ...
+type AccountDeletionResponse struct {
+	Data *AccountDeletion `json:"data"`
+}
+
+// DeleteAccountParams ...
+type DeleteAccountParams struct {
+	// Confirmation is the password of the user, or the code sent to them
+	// for accounts without a password.
+	Confirmation string `form:"confirmation"`
+}
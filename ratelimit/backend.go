@@ -0,0 +1,176 @@
+// Package ratelimit provides an icheck.Backend that limits the rate and
+// concurrency of calls made to the iCheck API.
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// Config configures a Backend.
+type Config struct {
+	// Limit applies to every call made through the backend.
+	Limit Limit
+	// Groups holds additional limits for groups of endpoints, keyed by the
+	// name returned by Group.
+	Groups map[string]Limit
+	// Group returns the endpoint group of a call. By default calls are
+	// grouped by the first segment of their path, e.g. "users" or
+	// "addresses".
+	Group func(method, path string) string
+	// MaxRetries is the number of times a call rejected with 429 Too Many
+	// Requests is retried once the API allows it. Calls whose Params.Body
+	// cannot be replayed, such as uploads, are never retried.
+	MaxRetries int
+}
+
+// Backend wraps an icheck.Backend and makes every call wait for its turn.
+// When the API answers 429 Too Many Requests, the limits the call went
+// through are paused for as long as the Retry-After or X-RateLimit-Reset
+// headers ask.
+type Backend struct {
+	B icheck.Backend
+
+	conf   Config
+	all    *limiter
+	mu     sync.Mutex
+	groups map[string]*limiter
+}
+
+// NewBackend returns a Backend that limits calls made through b.
+func NewBackend(b icheck.Backend, conf *Config) *Backend {
+	c := Config{}
+	if conf != nil {
+		c = *conf
+	}
+	if c.Group == nil {
+		c.Group = func(method, path string) string {
+			return icheck.RouteGroup(path)
+		}
+	}
+	return &Backend{
+		B:      b,
+		conf:   c,
+		all:    newLimiter(c.Limit),
+		groups: make(map[string]*limiter),
+	}
+}
+
+// Call is the Backend.Call implementation. It returns the context's error
+// if the context is done while waiting.
+func (b *Backend) Call(method, path string, form *icheck.RequestValues, params *icheck.Params, v interface{}) error {
+	p := icheck.Params{}
+	if params != nil {
+		p = *params
+	}
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	info := icheck.CallInfoFromContext(ctx)
+	if info == nil {
+		info = &icheck.CallInfo{}
+		ctx = icheck.WithCallInfo(ctx, info)
+	}
+	p.Context = ctx
+
+	limiters := []*limiter{b.all}
+	if l := b.group(b.conf.Group(method, path)); l != nil {
+		limiters = append(limiters, l)
+	}
+
+	for retries := 0; ; retries++ {
+		info.StatusCode = 0
+		err := b.call(ctx, limiters, func() error {
+			return b.B.Call(method, path, form, &p, v)
+		})
+		if err == nil || !tooManyRequests(info, err) {
+			return err
+		}
+
+		until := retryAt(info.Header, time.Now())
+		for _, l := range limiters {
+			l.pause(until)
+		}
+		if retries >= b.conf.MaxRetries || p.Body != nil && !icheck.Replayable(p.Body) {
+			return err
+		}
+		logrus.Debugf("Rate limited by Icheck, retrying %s %s after %v\n", method, path, until)
+	}
+}
+
+func (b *Backend) call(ctx context.Context, limiters []*limiter, fn func() error) error {
+	for _, l := range limiters {
+		if err := l.wait(ctx); err != nil {
+			return err
+		}
+	}
+	for i, l := range limiters {
+		if err := l.acquire(ctx); err != nil {
+			for _, held := range limiters[:i] {
+				held.release()
+			}
+			return err
+		}
+	}
+	defer func() {
+		for _, l := range limiters {
+			l.release()
+		}
+	}()
+	return fn()
+}
+
+// group returns the limiter of the named group, or nil if it has none.
+func (b *Backend) group(name string) *limiter {
+	limit, ok := b.conf.Groups[name]
+	if !ok {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	l, ok := b.groups[name]
+	if !ok {
+		l = newLimiter(limit)
+		b.groups[name] = l
+	}
+	return l
+}
+
+func tooManyRequests(info *icheck.CallInfo, err error) bool {
+	if info.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	e, ok := err.(*icheck.Error)
+	return ok && e.Status == http.StatusTooManyRequests
+}
+
+// retryAt returns when the API accepts calls again according to h. Without
+// any hint it backs off for one second.
+func retryAt(h http.Header, now time.Time) time.Time {
+	if s := h.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil {
+			return now.Add(time.Duration(secs) * time.Second)
+		}
+		if t, err := http.ParseTime(s); err == nil {
+			return t
+		}
+	}
+	if s := h.Get("X-RateLimit-Reset"); s != "" {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			// Small values are a delay in seconds, large ones a Unix time.
+			if n < 1e9 {
+				return now.Add(time.Duration(n) * time.Second)
+			}
+			return time.Unix(n, 0)
+		}
+	}
+	return now.Add(time.Second)
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestBackendRetriesTooManyRequests(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"status":429,"message":"slow down"}`))
+			return
+		}
+		w.Write([]byte(`{"status":200,"data":{"id":1}}`))
+	}))
+	defer srv.Close()
+
+	b := NewBackend(&icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}, &Config{MaxRetries: 1})
+	resp := &icheck.UserResponse{}
+	if err := b.Call("GET", "/users/1", nil, nil, resp); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
+
+func TestBackendDoesNotRetryStreamedBody(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"status":429,"message":"slow down"}`))
+	}))
+	defer srv.Close()
+
+	b := NewBackend(&icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}, &Config{MaxRetries: 2})
+	body := &icheck.MultipartBody{Files: []icheck.FilePart{{FieldName: "avatar", FileName: "a.png", Reader: strings.NewReader("PNGDATA")}}}
+	err := b.Call("POST", "/account", nil, &icheck.Params{Body: body}, &icheck.UserResponse{})
+	if e, ok := err.(*icheck.Error); !ok || e.Status != http.StatusTooManyRequests {
+		t.Errorf("err = %v, want the 429 error", err)
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
+
+func TestBackendWaitHonorsContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":200,"data":null}`))
+	}))
+	defer srv.Close()
+
+	b := NewBackend(&icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}, &Config{
+		Groups: map[string]Limit{"users": {Rate: 0.01, Burst: 1}},
+	})
+	if err := b.Call("GET", "/users/1", nil, nil, &icheck.UserResponse{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Call("GET", "/addresses", nil, nil, &icheck.AddressListResp{}); err != nil {
+		t.Fatalf("other groups should not be limited: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := b.Call("GET", "/users/2", nil, &icheck.Params{Context: ctx}, &icheck.UserResponse{})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit describes how fast and how many calls may be made at once.
+type Limit struct {
+	// Rate is the number of calls allowed per second. Zero means no rate
+	// limit.
+	Rate float64
+	// Burst is the number of calls that may be made at once before Rate
+	// kicks in. It defaults to one.
+	Burst int
+	// MaxInFlight is the number of calls that may be outstanding at the
+	// same time. Zero means no limit.
+	MaxInFlight int
+}
+
+// limiter enforces a single Limit. It combines a token bucket with a
+// semaphore and can be paused when the API asks us to back off.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	until  time.Time
+
+	sem chan struct{}
+}
+
+func newLimiter(l Limit) *limiter {
+	burst := float64(l.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	lim := &limiter{
+		rate:   l.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+	if l.MaxInFlight > 0 {
+		lim.sem = make(chan struct{}, l.MaxInFlight)
+	}
+	return lim
+}
+
+// wait blocks until a call may be made or ctx is done.
+func (l *limiter) wait(ctx context.Context) error {
+	for {
+		d := l.reserve(time.Now())
+		if d <= 0 {
+			return nil
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available and otherwise returns how long
+// to wait before trying again.
+func (l *limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.until) {
+		return l.until.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// pause stops handing out tokens until t and drains the bucket, so calls
+// resume at the configured rate afterwards.
+func (l *limiter) pause(t time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if t.After(l.until) {
+		l.until = t
+		l.tokens = 0
+		l.last = t
+	}
+}
+
+// acquire takes a slot from the semaphore, if any.
+func (l *limiter) acquire(ctx context.Context) error {
+	if l.sem == nil {
+		return nil
+	}
+	select {
+	case l.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release returns a slot taken by acquire.
+func (l *limiter) release() {
+	if l.sem != nil {
+		<-l.sem
+	}
+}
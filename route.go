@@ -0,0 +1,41 @@
+package icheck
+
+import "strings"
+
+// routeParams maps a collection segment to the name of the path parameter
+// that follows it.
+var routeParams = map[string]string{
+	"addresses":  "id",
+	"auth":       "provider",
+	"exports":    "id",
+	"identities": "provider",
+	"locations":  "id",
+	"sessions":   "id",
+	"users":      "id",
+}
+
+// RouteTemplate returns the route of path with its identifiers replaced by
+// placeholders, e.g. "/addresses/42" becomes "/addresses/{id}". Query strings
+// are dropped. It is meant for grouping calls in logs and metrics.
+func RouteTemplate(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		if name, ok := routeParams[segments[i-1]]; ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// RouteGroup returns the first segment of the route of path, e.g. "addresses"
+// for "/addresses/42". Calls in the same group touch the same resource.
+func RouteGroup(path string) string {
+	route := strings.TrimPrefix(RouteTemplate(path), "/")
+	if i := strings.IndexByte(route, '/'); i >= 0 {
+		route = route[:i]
+	}
+	return route
+}
@@ -13,6 +13,15 @@ type Client struct {
 
 // Search
 func (c *Client) Search(params url.Values) (*icheck.SearchResponse, error) {
+	env, err := c.SearchEnvelope(params)
+	if err != nil {
+		return nil, err
+	}
+	return &icheck.SearchResponse{Status: env.Status, Data: env.Data}, nil
+}
+
+// SearchEnvelope is Search returning the whole response envelope.
+func (c *Client) SearchEnvelope(params url.Values) (*icheck.Envelope[map[string]interface{}], error) {
 	body := &icheck.RequestValues{}
 	if params.Get("type") != "" {
 		body.Add("type", params.Get("type"))
@@ -26,10 +35,5 @@ func (c *Client) Search(params url.Values) (*icheck.SearchResponse, error) {
 	if params.Get("skip") != "" {
 		body.Add("skip", params.Get("skip"))
 	}
-	resp := &icheck.SearchResponse{}
-	err := c.B.Call("GET", "/search", body, nil, resp)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
+	return icheck.CallEnvelope[map[string]interface{}](c.B, "GET", "/search", body, nil)
 }
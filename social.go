@@ -0,0 +1,31 @@
+package icheck
+
+import "fmt"
+
+// SocialProvider is an identity provider users can log in with.
+type SocialProvider string
+
+const (
+	ProviderGoogle   SocialProvider = "google"
+	ProviderFacebook SocialProvider = "facebook"
+	ProviderApple    SocialProvider = "apple"
+	ProviderZalo     SocialProvider = "zalo"
+)
+
+// Valid reports whether p is a provider supported by the API.
+func (p SocialProvider) Valid() bool {
+	switch p {
+	case ProviderGoogle, ProviderFacebook, ProviderApple, ProviderZalo:
+		return true
+	}
+	return false
+}
+
+// ErrUnknownProvider is returned for providers not supported by the API.
+type ErrUnknownProvider struct {
+	Provider SocialProvider
+}
+
+func (e *ErrUnknownProvider) Error() string {
+	return fmt.Sprintf("icheck: unknown social provider %q", string(e.Provider))
+}
@@ -0,0 +1,198 @@
+package icheck
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// ErrInsecureToken is returned when an access token would be sent over
+// plain HTTP by a backend that does not allow it.
+var ErrInsecureToken = errors.New("icheck: refusing to send access token over plain HTTP")
+
+// ErrPinMismatch is returned when the certificate of an iCheck host matches
+// none of the pins of the transport.
+var ErrPinMismatch = errors.New("icheck: certificate does not match any pin")
+
+// TransportConfig tunes the HTTP client used to call the API. The zero
+// value gives the defaults of GetBackend.
+type TransportConfig struct {
+	// Timeout bounds a whole call, including reading the response.
+	// Defaults to 30 seconds.
+	Timeout time.Duration
+	// DialTimeout, TLSHandshakeTimeout and ResponseHeaderTimeout bound the
+	// steps of a call. They default to 10, 10 and 20 seconds.
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+
+	// MaxIdleConnsPerHost is the number of connections kept open to the
+	// API between calls. Defaults to 10.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the connections open to the API at once. Zero
+	// means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept. Defaults to 90
+	// seconds.
+	IdleConnTimeout time.Duration
+
+	// Proxy is the URL of the HTTP proxy to use. Defaults to the proxy of
+	// the HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables.
+	Proxy string
+
+	// CAFile and CABundle hold PEM encoded certificates trusted besides the
+	// system ones, e.g. those of a corporate TLS proxy.
+	CAFile   string
+	CABundle []byte
+
+	// Pins are the base64 encoded SHA-256 hashes of the public keys
+	// (SPKI) accepted for PinnedHosts. A connection succeeds if any
+	// certificate of the chain matches. No pinning is done if Pins is
+	// empty.
+	Pins []string
+	// PinnedHosts lists the hosts Pins apply to, along with their
+	// subdomains. Defaults to icheck.com.vn. Hosts are matched against the
+	// TLS server name, so hosts called by IP address are never pinned.
+	PinnedHosts []string
+}
+
+// NewHTTPClient returns an HTTP client configured by conf, which may be nil.
+func NewHTTPClient(conf *TransportConfig) (*http.Client, error) {
+	c := TransportConfig{}
+	if conf != nil {
+		c = *conf
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("icheck: invalid proxy URL %q", c.Proxy)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.CAFile != "" || len(c.CABundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		bundle := c.CABundle
+		if c.CAFile != "" {
+			data, err := os.ReadFile(c.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			bundle = append(append([]byte{}, bundle...), data...)
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, errors.New("icheck: no certificate found in CA bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(c.Pins) > 0 {
+		hosts := c.PinnedHosts
+		if hosts == nil {
+			hosts = []string{"icheck.com.vn"}
+		}
+		pins := make(map[string]bool, len(c.Pins))
+		for _, p := range c.Pins {
+			pins[p] = true
+		}
+		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			if !matchesHost(cs.ServerName, hosts) {
+				return nil
+			}
+			for _, cert := range cs.PeerCertificates {
+				sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+				if pins[base64.StdEncoding.EncodeToString(sum[:])] {
+					return nil
+				}
+			}
+			return ErrPinMismatch
+		}
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   durationOr(c.DialTimeout, 10*time.Second),
+		KeepAlive: 30 * time.Second,
+	}
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   durationOr(c.TLSHandshakeTimeout, 10*time.Second),
+		ResponseHeaderTimeout: durationOr(c.ResponseHeaderTimeout, 20*time.Second),
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   c.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       c.MaxConnsPerHost,
+		IdleConnTimeout:       durationOr(c.IdleConnTimeout, 90*time.Second),
+		ForceAttemptHTTP2:     true,
+	}
+	if transport.MaxIdleConnsPerHost == 0 {
+		transport.MaxIdleConnsPerHost = 10
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   durationOr(c.Timeout, defaultHTTPTimeout),
+	}, nil
+}
+
+// matchesHost reports whether host is one of hosts or a subdomain of one.
+func matchesHost(host string, hosts []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, h := range hosts {
+		h = strings.ToLower(h)
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+func durationOr(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
+
+// insecureWarned holds the hosts already warned about by checkTokenTransport.
+var insecureWarned sync.Map
+
+// checkTokenTransport returns ErrInsecureToken if an access token must not
+// be sent to u. Plain HTTP is only allowed to loopback hosts, or when allow
+// is set, in which case a warning is logged once per host.
+func checkTokenTransport(u *url.URL, allow bool) error {
+	if u.Scheme != "http" {
+		return nil
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	if !allow {
+		return ErrInsecureToken
+	}
+	if _, warned := insecureWarned.LoadOrStore(u.Host, true); !warned {
+		logrus.Warnf("Sending Icheck access token over plain HTTP to %s\n", u.Host)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package icheck
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewHTTPClientPinning(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":200}`))
+	}))
+	defer srv.Close()
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	sum := sha256.Sum256(srv.Certificate().RawSubjectPublicKeyInfo)
+	pin := base64.StdEncoding.EncodeToString(sum[:])
+
+	for _, tt := range []struct {
+		pin  string
+		want error
+	}{
+		{pin: pin},
+		{pin: base64.StdEncoding.EncodeToString(make([]byte, 32)), want: ErrPinMismatch},
+	} {
+		c, err := NewHTTPClient(&TransportConfig{
+			CABundle:    ca,
+			Pins:        []string{tt.pin},
+			PinnedHosts: []string{"example.com"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The test certificate is valid for example.com.
+		c.Transport.(*http.Transport).DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return net.Dial(network, srv.Listener.Addr().String())
+		}
+		b := &BackendConfiguration{URL: "https://example.com", HTTPClient: c}
+		err = b.Call("GET", "/account", nil, nil, &UserResponse{})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("pin %s: err = %v, want %v", tt.pin, err, tt.want)
+		}
+	}
+}
+
+func TestInsecureTokenRefused(t *testing.T) {
+	b := &BackendConfiguration{URL: "http://sandbox.example.com"}
+	_, err := b.NewRequest("GET", "/account", "", nil, &Params{AccessToken: "tok"})
+	if err != ErrInsecureToken {
+		t.Errorf("plain HTTP: err = %v, want ErrInsecureToken", err)
+	}
+
+	b.AllowInsecureTokens = true
+	if _, err := b.NewRequest("GET", "/account", "", nil, &Params{AccessToken: "tok"}); err != nil {
+		t.Errorf("allowed plain HTTP: err = %v", err)
+	}
+
+	b = &BackendConfiguration{URL: "http://127.0.0.1:8080"}
+	if _, err := b.NewRequest("GET", "/account", "", nil, &Params{AccessToken: "tok"}); err != nil {
+		t.Errorf("loopback: err = %v", err)
+	}
+}
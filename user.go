@@ -1,9 +1,15 @@
 package icheck
 
+import (
+	"io"
+	"time"
+)
+
 type User struct {
 	ID            int    `json:"id"`
 	IcheckID      string `json:"icheck_id"`
 	Avatar        string `json:"avatar"`
+	Cover         string `json:"cover"`
 	Name          string `json:"social_name"`
 	Phone         string `json:"phone"`
 	Email         string `json:"email"`
@@ -21,6 +27,45 @@ type AccessToken struct {
 // LoginResponse
 type LoginResponse struct {
 	Data *AccessToken
+	// MFA is set instead of Data when the user must enter a second factor.
+	MFA *MFAChallenge `json:"mfa,omitempty"`
+}
+
+// RevokedSession is the access token revoked by a logout.
+type RevokedSession struct {
+	ID        string    `json:"id"`
+	UserID    int       `json:"user_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// LogoutResponse
+type LogoutResponse struct {
+	Data *RevokedSession `json:"data"`
+}
+
+// Session is an active access token of a user.
+type Session struct {
+	ID string `json:"id"`
+	// Device describes the client the token was issued to, from its
+	// User-Agent.
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	// TTL is the lifetime of the token in seconds.
+	TTL int `json:"ttl"`
+	// Current is set on the session of the token making the call.
+	Current bool `json:"current"`
+}
+
+// SessionListResponse ...
+type SessionListResponse struct {
+	Data []*Session `json:"data"`
+}
+
+// RevokedSessionListResponse ...
+type RevokedSessionListResponse struct {
+	Data []*RevokedSession `json:"data"`
 }
 
 // UserResponse
@@ -30,9 +75,9 @@ type UserResponse struct {
 
 // LoginParams ...
 type LoginParams struct {
-	Username string
-	Password string
-	TTL      int64
+	Username string `form:"username,omitempty"`
+	Password string `form:"password,omitempty"`
+	TTL      int64  `form:"ttl,omitempty"`
 }
 
 type UserListResponse struct {
@@ -41,24 +86,53 @@ type UserListResponse struct {
 
 type UserListParams struct {
 	Params
-	IcheckID []string
+	IcheckID []string `form:"icheck_id"`
 }
 
 // RegisterParams
 type RegisterParams struct {
-	Username string
-	Password string
-	Name     string
+	Username string `form:"username,omitempty"`
+	Password string `form:"password,omitempty"`
+	Name     string `form:"name,omitempty"`
 }
 
 type LoginSocialParams struct {
-	Provider string
-	Code     string
-	TTL      int64
+	Provider SocialProvider
+	Code     string `form:"code,omitempty"`
+	// CodeVerifier and RedirectURI must match the authorization request when
+	// it used PKCE.
+	CodeVerifier string `form:"code_verifier,omitempty"`
+	RedirectURI  string `form:"redirect_uri,omitempty"`
+	TTL          int64  `form:"ttl,omitempty"`
 }
 
 type UserUpdateParams struct {
-	Name   string
-	Avatar string
-	Cover  string
+	Name   string `form:"name,omitempty"`
+	Avatar string `form:"avatar,omitempty"`
+	Cover  string `form:"cover,omitempty"`
+}
+
+// UploadParams configures an avatar or cover upload.
+type UploadParams struct {
+	Params
+	// MaxSize is the largest image uploaded, in bytes, after Resize.
+	// Defaults to DefaultMaxUploadSize.
+	MaxSize int64
+	// Resize, if set, transforms the image before the upload, e.g.
+	// resize.Fit from the user/resize package. It reads at most
+	// MaxResizeSize bytes. The content type is sniffed again from its
+	// result.
+	Resize func(io.Reader) (io.Reader, error)
+	// MaxResizeSize is the largest image given to Resize, in bytes.
+	// Defaults to DefaultMaxResizeSize.
+	MaxResizeSize int64
+	// Progress, if set, is called with the number of bytes sent so far.
+	Progress func(sent int64)
 }
+
+// DefaultMaxUploadSize is the default UploadParams.MaxSize.
+const DefaultMaxUploadSize = 5 << 20
+
+// DefaultMaxResizeSize is the default UploadParams.MaxResizeSize, enough for
+// the photos of phone cameras.
+const DefaultMaxResizeSize = 50 << 20
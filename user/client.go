@@ -11,50 +11,47 @@ type Client struct {
 
 // Login login user
 func (c *Client) Get(userID string, params *icheck.Params) (*icheck.User, error) {
-	resp := &icheck.UserResponse{}
-	err := c.B.Call("GET", "/users/"+userID, nil, params, resp)
+	env, err := c.GetEnvelope(userID, params)
 	if err != nil {
 		return nil, err
 	}
-	return resp.User, nil
+	return env.Data, nil
+}
+
+// GetEnvelope is Get returning the whole response envelope.
+func (c *Client) GetEnvelope(userID string, params *icheck.Params) (*icheck.Envelope[*icheck.User], error) {
+	return icheck.CallEnvelope[*icheck.User](c.B, "GET", "/users/"+userID, nil, params)
 }
 
 // List ...
 func (c *Client) List(params *icheck.UserListParams) ([]icheck.User, error) {
-	body := &icheck.RequestValues{}
-
-	if len(params.IcheckID) > 0 {
-		for _, userID := range params.IcheckID {
-			body.Add("icheck_id", userID)
-		}
-	}
-
-	resp := &icheck.UserListResponse{}
-	err := c.B.Call("GET", "/users", body, nil, resp)
+	env, err := c.ListEnvelope(params)
 	if err != nil {
 		return nil, err
 	}
-	return resp.Users, nil
+	return env.Data, nil
 }
 
-// Update ...
-func (c *Client) Update(data *icheck.UserUpdateParams, params *icheck.Params) (interface{}, error) {
-	body := &icheck.RequestValues{}
-	if data.Name != "" {
-		body.Add("name", data.Name)
-	}
-	if data.Avatar != "" {
-		body.Add("avatar", data.Avatar)
+// ListEnvelope is List returning the whole response envelope.
+func (c *Client) ListEnvelope(params *icheck.UserListParams) (*icheck.Envelope[[]icheck.User], error) {
+	body, err := icheck.EncodeForm(params)
+	if err != nil {
+		return nil, err
 	}
+	return icheck.CallEnvelope[[]icheck.User](c.B, "GET", "/users", body, nil)
+}
 
-	if data.Cover != "" {
-		body.Add("cover", data.Cover)
+// Update updates the profile of the current user and returns it
+func (c *Client) Update(data *icheck.UserUpdateParams, params *icheck.Params) (*icheck.User, error) {
+	body, err := icheck.EncodeForm(data)
+	if err != nil {
+		return nil, err
 	}
 
-	resp := make(map[string]interface{})
-	err := c.B.Call("POST", "/account", body, params, resp)
+	resp := &icheck.UserResponse{}
+	err = c.B.Call("POST", "/account", body, params, resp)
 	if err != nil {
 		return nil, err
 	}
-	return resp["data"], nil
+	return resp.User, nil
 }
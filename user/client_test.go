@@ -0,0 +1,31 @@
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestUpdate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/account" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		if name := r.PostFormValue("name"); name != "Nam" {
+			t.Errorf("name = %q", name)
+		}
+		w.Write([]byte(`{"status":200,"data":{"id":7,"social_name":"Nam"}}`))
+	}))
+	defer srv.Close()
+	c := &Client{B: &icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}}
+
+	user, err := c.Update(&icheck.UserUpdateParams{Name: "Nam"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 7 || user.Name != "Nam" {
+		t.Errorf("user = %+v", user)
+	}
+}
@@ -0,0 +1,86 @@
+// Package resize shrinks images before they are uploaded with
+// user.Client.UploadAvatar or UploadCover. It is kept apart from the user
+// package so that only programs resizing images depend on
+// golang.org/x/image.
+package resize
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+)
+
+// Fit returns a function shrinking JPEG, PNG and GIF images to fit within
+// maxWidth x maxHeight, keeping their aspect ratio, for use as
+// icheck.UploadParams.Resize. A zero bound is not enforced. Images that are
+// already small enough, or in a format that cannot be decoded, are returned
+// unchanged. GIF images are re-encoded as PNG.
+func Fit(maxWidth, maxHeight int) func(io.Reader) (io.Reader, error) {
+	return func(r io.Reader) (io.Reader, error) {
+		return fit(r, maxWidth, maxHeight)
+	}
+}
+
+func fit(r io.Reader, maxWidth, maxHeight int) (io.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var src image.Image
+	format := ""
+	switch {
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		format = "jpeg"
+		src, err = jpeg.Decode(bytes.NewReader(data))
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		format = "png"
+		src, err = png.Decode(bytes.NewReader(data))
+	case bytes.HasPrefix(data, []byte("GIF8")):
+		format = "gif"
+		src, err = gif.Decode(bytes.NewReader(data))
+	default:
+		return bytes.NewReader(data), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b := src.Bounds()
+	scale := 1.0
+	if maxWidth > 0 && b.Dx() > maxWidth {
+		scale = float64(maxWidth) / float64(b.Dx())
+	}
+	if maxHeight > 0 && float64(b.Dy())*scale > float64(maxHeight) {
+		scale = float64(maxHeight) / float64(b.Dy())
+	}
+	if scale == 1.0 && format != "gif" {
+		return bytes.NewReader(data), nil
+	}
+
+	w, h := int(float64(b.Dx())*scale), int(float64(b.Dy())*scale)
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+
+	buf := &bytes.Buffer{}
+	if format == "jpeg" {
+		err = jpeg.Encode(buf, dst, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(buf, dst)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
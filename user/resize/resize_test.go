@@ -0,0 +1,56 @@
+package resize
+
+import (
+	"bytes"
+	"image"
+	"image/color/palette"
+	"image/gif"
+	"image/png"
+	"io"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 100, 50)))
+
+	r, err := Fit(40, 10)(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf, format, err := image.DecodeConfig(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" || conf.Width != 20 || conf.Height != 10 {
+		t.Errorf("got %s %dx%d, want png 20x10", format, conf.Width, conf.Height)
+	}
+}
+
+func TestFitConvertsGIF(t *testing.T) {
+	buf := &bytes.Buffer{}
+	gif.Encode(buf, image.NewPaletted(image.Rect(0, 0, 8, 8), palette.Plan9), nil)
+
+	r, err := Fit(100, 100)(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, format, err := image.DecodeConfig(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" {
+		t.Errorf("format = %s, want png", format)
+	}
+}
+
+func TestFitPassesThroughUnknownFormats(t *testing.T) {
+	r, err := Fit(10, 10)(bytes.NewReader([]byte("RIFF....WEBP")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	if string(data) != "RIFF....WEBP" {
+		t.Errorf("data = %q", data)
+	}
+}
@@ -0,0 +1,128 @@
+package user
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// ErrImageTooLarge is returned when an upload exceeds UploadParams.MaxSize,
+// or UploadParams.MaxResizeSize before resizing.
+var ErrImageTooLarge = errors.New("icheck: image exceeds the maximum upload size")
+
+// ErrUnsupportedImage is returned when an upload is not a JPEG, PNG, GIF or
+// WebP image.
+var ErrUnsupportedImage = errors.New("icheck: unsupported image type")
+
+var imageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// UploadAvatar uploads r as the avatar of the current user and returns its
+// new URL.
+func (c *Client) UploadAvatar(ctx context.Context, r io.Reader, filename string, params *icheck.UploadParams) (string, error) {
+	user, err := c.upload(ctx, "avatar", r, filename, params)
+	if err != nil {
+		return "", err
+	}
+	return user.Avatar, nil
+}
+
+// UploadCover uploads r as the cover of the current user and returns its new
+// URL.
+func (c *Client) UploadCover(ctx context.Context, r io.Reader, filename string, params *icheck.UploadParams) (string, error) {
+	user, err := c.upload(ctx, "cover", r, filename, params)
+	if err != nil {
+		return "", err
+	}
+	return user.Cover, nil
+}
+
+func (c *Client) upload(ctx context.Context, field string, r io.Reader, filename string, params *icheck.UploadParams) (*icheck.User, error) {
+	conf := icheck.UploadParams{}
+	if params != nil {
+		conf = *params
+	}
+	if conf.MaxSize <= 0 {
+		conf.MaxSize = icheck.DefaultMaxUploadSize
+	}
+	if conf.MaxResizeSize <= 0 {
+		conf.MaxResizeSize = icheck.DefaultMaxResizeSize
+	}
+
+	// MaxSize applies to the resized image, which is what is uploaded.
+	if conf.Resize != nil {
+		resized, err := conf.Resize(&uploadReader{r: r, max: conf.MaxResizeSize})
+		if err != nil {
+			if errors.Is(err, ErrImageTooLarge) {
+				return nil, ErrImageTooLarge
+			}
+			return nil, err
+		}
+		r = resized
+	}
+
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head)
+	if !imageTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedImage, contentType)
+	}
+
+	body := &uploadReader{r: br, max: conf.MaxSize, progress: conf.Progress}
+
+	call := conf.Params
+	call.Context = ctx
+	call.Body = &icheck.MultipartBody{
+		Files: []icheck.FilePart{{
+			FieldName:   field,
+			FileName:    filename,
+			ContentType: contentType,
+			Reader:      body,
+		}},
+	}
+
+	resp := &icheck.UserResponse{}
+	err = c.B.Call("POST", "/account", nil, &call, resp)
+	if err != nil {
+		if errors.Is(err, ErrImageTooLarge) {
+			return nil, ErrImageTooLarge
+		}
+		return nil, err
+	}
+	if resp.User == nil {
+		return &icheck.User{}, nil
+	}
+	return resp.User, nil
+}
+
+// uploadReader fails once more than max bytes are read and reports progress.
+type uploadReader struct {
+	r        io.Reader
+	max      int64
+	sent     int64
+	progress func(sent int64)
+}
+
+func (u *uploadReader) Read(p []byte) (int, error) {
+	n, err := u.r.Read(p)
+	u.sent += int64(n)
+	if u.sent > u.max {
+		return 0, ErrImageTooLarge
+	}
+	if n > 0 && u.progress != nil {
+		u.progress(u.sent)
+	}
+	return n, err
+}
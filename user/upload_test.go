@@ -0,0 +1,110 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	icheck "github.com/icheckteam/icheck-go"
+	"github.com/icheckteam/icheck-go/user/resize"
+)
+
+func testPNG(w, h int) []byte {
+	buf := &bytes.Buffer{}
+	png.Encode(buf, image.NewRGBA(image.Rect(0, 0, w, h)))
+	return buf.Bytes()
+}
+
+func TestUploadAvatar(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, h, err := r.FormFile("avatar")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if ct := h.Header.Get("Content-Type"); ct != "image/png" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		conf, _, err := image.DecodeConfig(f)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if conf.Width != 10 || conf.Height != 5 {
+			t.Errorf("uploaded %dx%d, want 10x5", conf.Width, conf.Height)
+		}
+		w.Write([]byte(`{"status":200,"data":{"avatar":"https://cdn.icheck.vn/a.png"}}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{B: &icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}}
+	var sent int64
+	url, err := c.UploadAvatar(context.Background(), bytes.NewReader(testPNG(100, 50)), "me.png", &icheck.UploadParams{
+		Resize:   resize.Fit(10, 0),
+		Progress: func(n int64) { sent = n },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://cdn.icheck.vn/a.png" {
+		t.Errorf("url = %q", url)
+	}
+	if sent == 0 {
+		t.Error("progress was not reported")
+	}
+}
+
+func TestUploadRejectsInvalidImages(t *testing.T) {
+	c := &Client{B: &icheck.BackendConfiguration{URL: "http://127.0.0.1:0", HTTPClient: http.DefaultClient}}
+
+	_, err := c.UploadCover(context.Background(), bytes.NewReader([]byte("plain text")), "a.txt", nil)
+	if err == nil {
+		t.Error("text was accepted")
+	}
+
+	_, err = c.UploadCover(context.Background(), bytes.NewReader(testPNG(100, 100)), "a.png", &icheck.UploadParams{MaxResizeSize: 10, Resize: resize.Fit(50, 0)})
+	if err != ErrImageTooLarge {
+		t.Errorf("image to resize: got %v, want ErrImageTooLarge", err)
+	}
+}
+
+func TestUploadResizesLargeImages(t *testing.T) {
+	// Random pixels do not compress: the PNG is far above MaxSize until it
+	// is resized.
+	img := image.NewRGBA(image.Rect(0, 0, 200, 200))
+	rand.New(rand.NewSource(1)).Read(img.Pix)
+	buf := &bytes.Buffer{}
+	png.Encode(buf, img)
+	const maxSize = 4 << 10
+	if buf.Len() <= maxSize {
+		t.Fatalf("test image is only %d bytes", buf.Len())
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := r.FormFile("cover"); err != nil {
+			// The client gave up sending an image over MaxSize.
+			return
+		}
+		w.Write([]byte(`{"status":200,"data":{"cover":"https://cdn.icheck.vn/c.png"}}`))
+	}))
+	defer srv.Close()
+	c := &Client{B: &icheck.BackendConfiguration{URL: srv.URL, HTTPClient: srv.Client()}}
+
+	_, err := c.UploadCover(context.Background(), bytes.NewReader(buf.Bytes()), "c.png", &icheck.UploadParams{MaxSize: maxSize, Resize: resize.Fit(150, 150)})
+	if err != ErrImageTooLarge {
+		t.Errorf("barely resized: got %v, want ErrImageTooLarge", err)
+	}
+
+	url, err := c.UploadCover(context.Background(), buf, "c.png", &icheck.UploadParams{MaxSize: maxSize, Resize: resize.Fit(20, 20)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://cdn.icheck.vn/c.png" {
+		t.Errorf("url = %q", url)
+	}
+}
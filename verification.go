@@ -0,0 +1,51 @@
+package icheck
+
+import (
+	"fmt"
+	"time"
+)
+
+// VerificationChannel is where a verification code is sent.
+type VerificationChannel string
+
+const (
+	VerificationPhone VerificationChannel = "phone"
+	VerificationEmail VerificationChannel = "email"
+)
+
+// Verification describes a verification code that was sent to the user.
+type Verification struct {
+	Channel VerificationChannel `json:"channel"`
+	// Destination is the masked phone number or email address the code was
+	// sent to.
+	Destination string    `json:"destination"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	// ResendAt is the earliest time a new code may be requested.
+	ResendAt time.Time `json:"resend_at"`
+}
+
+// VerificationResponse
+type VerificationResponse struct {
+	Data *Verification `json:"data"`
+}
+
+// VerificationParams ...
+type VerificationParams struct {
+	Channel VerificationChannel `form:"channel"`
+	Resend  bool                `form:"resend,omitempty"`
+}
+
+// ConfirmVerificationParams ...
+type ConfirmVerificationParams struct {
+	Code string `form:"code"`
+}
+
+// ErrResendCooldown is returned when a verification code is requested again
+// before the API allows it.
+type ErrResendCooldown struct {
+	RetryAt time.Time
+}
+
+func (e *ErrResendCooldown) Error() string {
+	return fmt.Sprintf("icheck: verification code cannot be resent before %s", e.RetryAt.Format(time.RFC3339))
+}
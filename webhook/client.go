@@ -1,10 +1,36 @@
+// Package webhook verifies and parses the events the iCheck API sends to
+// webhook endpoints.
 package webhook
 
 import (
 	"crypto/hmac"
 	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+// SignatureHeader is the header carrying the signature of an event.
+const SignatureHeader = "Icheck-Signature"
+
+// DefaultTolerance is the largest accepted age of an event.
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrNotSigned is returned when the signature header is missing or
+	// malformed.
+	ErrNotSigned = errors.New("icheck: webhook has no valid signature header")
+	// ErrInvalidSignature is returned when no signature matches the payload.
+	ErrInvalidSignature = errors.New("icheck: webhook signature does not match")
+	// ErrTooOld is returned for events older than the tolerance, which may be
+	// replayed.
+	ErrTooOld = errors.New("icheck: webhook timestamp outside tolerance")
 )
 
 // Computes a webhook signature using Stripe's v1 signing method. See
@@ -16,3 +42,70 @@ func computeSignature(t time.Time, payload []byte, appID string, secret string)
 	mac.Write(payload)
 	return mac.Sum(nil)
 }
+
+// ConstructEvent checks that payload was signed by the API for appID and
+// secret, and parses it. header is the value of SignatureHeader, of the form
+// "t=1492774577,v1=5257a869...".
+func ConstructEvent(payload []byte, header, appID, secret string) (*icheck.Event, error) {
+	return ConstructEventWithTolerance(payload, header, appID, secret, DefaultTolerance)
+}
+
+// ConstructEventWithTolerance is ConstructEvent accepting events up to
+// tolerance old.
+func ConstructEventWithTolerance(payload []byte, header, appID, secret string, tolerance time.Duration) (*icheck.Event, error) {
+	t, signatures, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(t) > tolerance {
+		return nil, ErrTooOld
+	}
+
+	expected := computeSignature(t, payload, appID, secret)
+	valid := false
+	for _, sig := range signatures {
+		if hmac.Equal(expected, sig) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, ErrInvalidSignature
+	}
+
+	e := &icheck.Event{}
+	if err := json.Unmarshal(payload, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// parseHeader returns the timestamp and v1 signatures of header.
+func parseHeader(header string) (time.Time, [][]byte, error) {
+	var t time.Time
+	var signatures [][]byte
+	for _, pair := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 {
+			return time.Time{}, nil, ErrNotSigned
+		}
+		switch kv[0] {
+		case "t":
+			ts, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return time.Time{}, nil, ErrNotSigned
+			}
+			t = time.Unix(ts, 0)
+		case "v1":
+			sig, err := hex.DecodeString(kv[1])
+			if err != nil {
+				continue
+			}
+			signatures = append(signatures, sig)
+		}
+	}
+	if t.IsZero() || len(signatures) == 0 {
+		return time.Time{}, nil, ErrNotSigned
+	}
+	return t, signatures, nil
+}
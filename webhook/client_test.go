@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	icheck "github.com/icheckteam/icheck-go"
+)
+
+func TestConstructAccountDeletedEvent(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"account.deleted","data":{"user_id":7,"status":"completed"}}`)
+	now := time.Now()
+	header := fmt.Sprintf("t=%d,v1=%s", now.Unix(), hex.EncodeToString(computeSignature(now, payload, "app", "secret")))
+
+	e, err := ConstructEvent(payload, header, "app", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := e.AccountDeletion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.UserID != 7 || d.Status != icheck.AccountDeletionCompleted {
+		t.Errorf("deletion = %+v", d)
+	}
+
+	if _, err := ConstructEvent(payload, header, "app", "other"); err != ErrInvalidSignature {
+		t.Errorf("wrong secret: err = %v", err)
+	}
+	if _, err := ConstructEvent(payload, "v1=abc", "app", "secret"); err != ErrNotSigned {
+		t.Errorf("no timestamp: err = %v", err)
+	}
+	old := now.Add(-time.Hour)
+	header = fmt.Sprintf("t=%d,v1=%s", old.Unix(), hex.EncodeToString(computeSignature(old, payload, "app", "secret")))
+	if _, err := ConstructEvent(payload, header, "app", "secret"); err != ErrTooOld {
+		t.Errorf("old event: err = %v", err)
+	}
+}